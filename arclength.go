@@ -0,0 +1,298 @@
+package graphics2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Arc-length parameterization of a path. The first call to any of the methods below builds a table
+// for each part of the path, mapping t to the cumulative length along the part, by adaptively
+// subdividing the part until its pieces are flat and integrating the speed of each piece with
+// Gauss-Legendre quadrature. The table is cached on the path and lengths are converted back to t by
+// interpolating the table and then refining with Newton's method.
+//
+// Where a method takes or returns a t for the whole path, t is in [0, 1] and is shared equally
+// between the parts returned by Parts(), i.e. t = (i + ti) / n where ti is the t within part i and
+// n is the number of parts.
+
+// ArcLengthFlatten is the flatness, relative to the size of a part, used when building a path's
+// arc-length table.
+var ArcLengthFlatten = 1e-3
+
+type arcTable struct {
+	parts  []Part
+	starts []float64   // Cumulative length at the start of each part, plus the total
+	ts     [][]float64 // t values for each part
+	ls     [][]float64 // Cumulative length within the part at each t
+}
+
+func newArcTable(parts []Part) *arcTable {
+	return newArcTableFlat(parts, 0)
+}
+
+// newArcTableFlat builds the table with each part subdivided until its pieces are flat to within d,
+// or to within ArcLengthFlatten of the part's size if d isn't positive.
+func newArcTableFlat(parts []Part, d float64) *arcTable {
+	n := len(parts)
+	res := &arcTable{parts, make([]float64, n+1), make([][]float64, n), make([][]float64, n)}
+	sum := 0.0
+	for i, part := range parts {
+		res.starts[i] = sum
+		ts, ls := []float64{0}, []float64{0}
+		if len(part) > 1 {
+			pd := d
+			if pd <= 0 {
+				pd = bbDiag(part) * ArcLengthFlatten
+			}
+			ts, ls = arcSamples(math.Max(pd, 1e-12), part, part, 0, 1, ts, ls)
+		}
+		res.ts[i], res.ls[i] = ts, ls
+		sum += ls[len(ls)-1]
+	}
+	res.starts[n] = sum
+	return res
+}
+
+// arcSamples appends the t value and cumulative length of the end of each flat piece of the part.
+func arcSamples(d float64, orig, part Part, t0, t1 float64, ts, ls []float64) ([]float64, []float64) {
+	if flatWithin(d, part) || t1-t0 < 1e-9 {
+		l := ls[len(ls)-1] + partArcLength(orig, t0, t1)
+		return append(ts, t1), append(ls, l)
+	}
+	l, r := splitPart(part, 0.5)
+	tm := (t0 + t1) / 2
+	ts, ls = arcSamples(d, orig, l, t0, tm, ts, ls)
+	return arcSamples(d, orig, r, tm, t1, ts, ls)
+}
+
+// Five point Gauss-Legendre abscissae and weights for [-1, 1]
+var (
+	glX = []float64{0, -0.5384693101056831, 0.5384693101056831, -0.9061798459386640, 0.9061798459386640}
+	glW = []float64{0.5688888888888889, 0.4786286704993665, 0.4786286704993665, 0.2369268850561891, 0.2369268850561891}
+)
+
+// partArcLength returns the length of the part between t0 and t1 by integrating its speed.
+func partArcLength(part Part, t0, t1 float64) float64 {
+	n := len(part) - 1
+	if n == 1 {
+		return (t1 - t0) * util.DistanceE(part[0], part[1])
+	}
+	hw, mid := (t1-t0)/2, (t0+t1)/2
+	sum := 0.0
+	for i, x := range glX {
+		dp := util.DeCasteljau(part, mid+hw*x)
+		sum += glW[i] * math.Hypot(dp[2], dp[3])
+	}
+	return sum * hw * float64(n)
+}
+
+// length returns the length along the part at t.
+func (at *arcTable) length(i int, t float64) float64 {
+	ts, ls := at.ts[i], at.ls[i]
+	k := sort.SearchFloat64s(ts, t)
+	if k == 0 {
+		return 0
+	}
+	if k == len(ts) {
+		return ls[k-1]
+	}
+	return ls[k-1] + partArcLength(at.parts[i], ts[k-1], t)
+}
+
+// partT returns the part index and the t within that part at length s along the path.
+func (at *arcTable) partT(s float64) (int, float64) {
+	n := len(at.parts)
+	if n == 0 || s <= 0 {
+		return 0, 0
+	}
+	if s >= at.starts[n] {
+		return n - 1, 1
+	}
+	// First part ending after s
+	i := sort.Search(n, func(j int) bool { return at.starts[j+1] > s })
+	s -= at.starts[i]
+	ts, ls := at.ts[i], at.ls[i]
+	k := sort.SearchFloat64s(ls, s)
+	if k == 0 {
+		return i, 0
+	}
+	if k == len(ls) {
+		return i, 1
+	}
+	dl := ls[k] - ls[k-1]
+	if dl == 0 {
+		return i, ts[k]
+	}
+	// Interpolate and then refine
+	lo, hi := ts[k-1], ts[k]
+	t := lo + (s-ls[k-1])/dl*(hi-lo)
+	part := at.parts[i]
+	for range 4 {
+		dp := util.DeCasteljau(part, t)
+		sp := math.Hypot(dp[2], dp[3]) * float64(len(part)-1)
+		if sp == 0 {
+			break
+		}
+		nt := t - (at.length(i, t)-s)/sp
+		if nt < lo || nt > hi {
+			break
+		}
+		t = nt
+	}
+	return i, t
+}
+
+// arcLengths returns the cached arc-length table for the path, building it if necessary.
+func (p *Path) arcLengths() *arcTable {
+	if p.arclen == nil {
+		p.arclen = newArcTable(p.Parts())
+	}
+	return p.arclen
+}
+
+// ArcLength returns the length of the path as determined by its arc-length table.
+func (p *Path) ArcLength() float64 {
+	at := p.arcLengths()
+	return at.starts[len(at.parts)]
+}
+
+// LengthAtT returns the distance along the path at t, where t is in [0, 1] and is shared equally between
+// the path's parts.
+func (p *Path) LengthAtT(t float64) float64 {
+	at := p.arcLengths()
+	n := len(at.parts)
+	if n == 0 || t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return at.starts[n]
+	}
+	i, pt := partIndexT(t, n)
+	return at.starts[i] + at.length(i, pt)
+}
+
+// TAtLength returns t, as used by LengthAtT, at distance s along the path. s is clamped to the path length.
+func (p *Path) TAtLength(s float64) float64 {
+	n := len(p.arcLengths().parts)
+	if n == 0 {
+		return 0
+	}
+	i, t := p.PartAtLength(s)
+	return (float64(i) + t) / float64(n)
+}
+
+// PartAtLength returns the index of the part, as returned by Parts(), and the t within the part at
+// distance s along the path. s is clamped to the path length.
+func (p *Path) PartAtLength(s float64) (int, float64) {
+	return p.arcLengths().partT(s)
+}
+
+// PointAtLength returns the point at distance s along the path. s is clamped to the path length.
+// Returns nil if the path has no steps.
+func (p *Path) PointAtLength(s float64) []float64 {
+	at := p.arcLengths()
+	if len(at.parts) == 0 {
+		return nil
+	}
+	i, t := at.partT(s)
+	pt := util.DeCasteljau(at.parts[i], t)
+	return []float64{pt[0], pt[1]}
+}
+
+// TangentAtLength returns the normalized tangent at distance s along the path. s is clamped to the
+// path length. The tangent of a point, or of a path with no steps, is {0, 0}.
+func (p *Path) TangentAtLength(s float64) []float64 {
+	at := p.arcLengths()
+	if len(at.parts) == 0 {
+		return []float64{0, 0}
+	}
+	i, t := at.partT(s)
+	dx, dy := partTangent(at.parts[i], t)
+	return []float64{dx, dy}
+}
+
+// NormalAtLength returns the normalized right hand side normal, as used by TraceProc, at distance s
+// along the path. s is clamped to the path length.
+func (p *Path) NormalAtLength(s float64) []float64 {
+	tang := p.TangentAtLength(s)
+	return []float64{tang[1], 0 - tang[0]}
+}
+
+// lengthsEvery returns the distances along the path, l apart, starting at 0. If end is set, the path
+// length is appended unless it coincides with the last distance.
+func (p *Path) lengthsEvery(l float64, end bool) []float64 {
+	total := p.ArcLength()
+	l = math.Abs(l)
+	res := []float64{0}
+	if l > 0 {
+		for k := 1; ; k++ {
+			s := float64(k) * l
+			if s >= total || util.Equals(s, total) {
+				break
+			}
+			res = append(res, s)
+		}
+	}
+	if end && total > 0 {
+		res = append(res, total)
+	}
+	return res
+}
+
+// pointsEvery returns the points along the path, l apart, from its start to its end. The last gap is
+// whatever length of the path remains. A path with no steps has no points.
+func (p *Path) pointsEvery(l float64) [][]float64 {
+	if len(p.steps) == 0 {
+		return nil
+	}
+	if len(p.arcLengths().parts) == 0 {
+		return [][]float64{p.steps[0][0]}
+	}
+	lens := p.lengthsEvery(l, true)
+	res := make([][]float64, len(lens))
+	for i, s := range lens {
+		res[i] = p.PointAtLength(s)
+	}
+	return res
+}
+
+// partIndexT converts a path t into a part index and the t within that part.
+func partIndexT(t float64, n int) (int, float64) {
+	ft := t * float64(n)
+	i := int(math.Floor(ft))
+	if i >= n {
+		return n - 1, 1
+	}
+	if i < 0 {
+		return 0, 0
+	}
+	return i, ft - float64(i)
+}
+
+// partTangent returns the normalized tangent of the part at t. Where the derivative vanishes, such as
+// at an end point coincident with a control point, the tangent is taken from the nearby curve.
+func partTangent(part Part, t float64) (float64, float64) {
+	if len(part) < 2 {
+		return 0, 0
+	}
+	dp := util.DeCasteljau(part, t)
+	if d := math.Hypot(dp[2], dp[3]); d > 0 {
+		return dp[2] / d, dp[3] / d
+	}
+	// Use the direction to a nearby point
+	const dt = 1e-6
+	var p0, p1 []float64
+	if t < 0.5 {
+		p0, p1 = util.DeCasteljau(part, t), util.DeCasteljau(part, t+dt)
+	} else {
+		p0, p1 = util.DeCasteljau(part, t-dt), util.DeCasteljau(part, t)
+	}
+	dx, dy := p1[0]-p0[0], p1[1]-p0[1]
+	if d := math.Hypot(dx, dy); d > 0 {
+		return dx / d, dy / d
+	}
+	last := part[len(part)-1]
+	return unit(last[0]-part[0][0], last[1]-part[0][1])
+}
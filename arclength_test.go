@@ -0,0 +1,31 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates finding the points, tangents and normals at regular distances along a path.
+func ExamplePath_PointAtLength() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{100, 0})
+	path.AddStep([]float64{100, 100}, []float64{200, 100})
+
+	fmt.Printf("Length %.2f\n", path.ArcLength())
+	for _, s := range []float64{0, 50, 100, 150, 200} {
+		pt, tang, norm := path.PointAtLength(s), path.TangentAtLength(s), path.NormalAtLength(s)
+		fmt.Printf("%.0f: point %.2f,%.2f tangent %.2f,%.2f normal %.2f,%.2f\n",
+			s, pt[0], pt[1], tang[0], tang[1], norm[0], norm[1])
+	}
+
+	empty := &g2d.Path{}
+	fmt.Println("Empty:", empty.PointAtLength(10), empty.TangentAtLength(10))
+	// Output:
+	// Length 262.32
+	// 0: point 0.00,0.00 tangent 1.00,0.00 normal 0.00,-1.00
+	// 50: point 50.00,0.00 tangent 1.00,0.00 normal 0.00,-1.00
+	// 100: point 100.00,0.00 tangent 0.00,1.00 normal 1.00,0.00
+	// 150: point 108.18,49.02 tangent 0.37,0.93 normal 0.93,-0.37
+	// 200: point 139.90,86.44 tangent 0.86,0.50 normal 0.50,-0.86
+	// Empty: [] [0 0]
+}
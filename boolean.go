@@ -0,0 +1,470 @@
+package graphics2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// BooleanOp describes how two shapes are combined.
+type BooleanOp int
+
+// Constants for boolean operations.
+const (
+	BoolUnion BooleanOp = iota
+	BoolIntersect
+	BoolDifference
+	BoolXor
+)
+
+// BooleanProc combines the shape being processed with Shape using Op. The shape being
+// processed is the first operand.
+type BooleanProc struct {
+	Op    BooleanOp
+	Shape *Shape
+}
+
+// Process implements the ShapeProcessor interface.
+func (bp BooleanProc) Process(s *Shape) []*Shape {
+	return []*Shape{BooleanShapes(bp.Op, s, bp.Shape)}
+}
+
+// Union returns a new shape describing the area covered by either shape.
+func (s *Shape) Union(o *Shape) *Shape {
+	return BooleanShapes(BoolUnion, s, o)
+}
+
+// Intersect returns a new shape describing the area covered by both shapes.
+func (s *Shape) Intersect(o *Shape) *Shape {
+	return BooleanShapes(BoolIntersect, s, o)
+}
+
+// Difference returns a new shape describing the area covered by this shape but not the other.
+func (s *Shape) Difference(o *Shape) *Shape {
+	return BooleanShapes(BoolDifference, s, o)
+}
+
+// Xor returns a new shape describing the area covered by exactly one of the shapes.
+func (s *Shape) Xor(o *Shape) *Shape {
+	return BooleanShapes(BoolXor, s, o)
+}
+
+// BooleanShapes returns a new shape containing the closed outlines that result from applying the
+// boolean operation to the two shapes. Whether a point is inside a shape is determined by the
+// shape's fill rule. The parts of the shapes' paths are split where they cross and the pieces that
+// bound the result are kept, so curve steps retain their order. Outlines are oriented so that the
+// filled area is always on the left (i.e. holes run in the opposite direction to their enclosing
+// outlines) and the result renders identically under either fill rule.
+func BooleanShapes(op BooleanOp, a, b *Shape) *Shape {
+	ra, rb := NonZero, NonZero
+	if a != nil {
+		ra = a.rule
+	}
+	if b != nil {
+		rb = b.rule
+	}
+	return resolveShapes([]*Shape{a, b}, func(w []int) bool {
+		ia, ib := ra.Inside(w[0]), rb.Inside(w[1])
+		switch op {
+		default:
+			fallthrough
+		case BoolUnion:
+			return ia || ib
+		case BoolIntersect:
+			return ia && ib
+		case BoolDifference:
+			return ia && !ib
+		case BoolXor:
+			return ia != ib
+		}
+	})
+}
+
+// bedge is a piece of a part from one of the operands. Parts are broken into pieces at their
+// extremities so no piece can intersect itself.
+type bedge struct {
+	part   Part
+	orig   int     // Index of the part the edge came from
+	t0, t1 float64 // Range of the edge in the original part
+	op     int     // Operand the edge belongs to
+	bb     [][]float64
+	splits [][]float64 // {t, x, y} where t is local to the edge
+}
+
+// bfrag is a piece of an edge between two intersections.
+type bfrag struct {
+	part   Part
+	orig   int
+	t0, t1 float64
+	sv, ev int // Start and end vertex ids
+	rev    bool
+}
+
+// resolveShapes splits the paths of the shapes where they cross and then keeps the pieces
+// that separate inside from outside, as defined by the inside function. The inside function is
+// passed the winding number of each shape at a point. The pieces are linked into closed paths
+// with the inside always on the left.
+func resolveShapes(shapes []*Shape, inside func([]int) bool) *Shape {
+	// Collect the closed parts and break them into edges
+	origs := []Part{}
+	edges := []*bedge{}
+	opEdges := make([][]*bedge, len(shapes))
+	for k, shape := range shapes {
+		if shape == nil {
+			continue
+		}
+		for _, path := range shape.paths {
+			for _, part := range closedParts(path) {
+				if partDegenerate(part) {
+					continue
+				}
+				oi := len(origs)
+				origs = append(origs, part)
+				ts := []float64{0, 1}
+				if len(part) > 2 {
+					ts = util.CalcExtremities(part)
+				}
+				for i := 1; i < len(ts); i++ {
+					t0, t1 := ts[i-1], ts[i]
+					if t1-t0 < 1e-9 {
+						continue
+					}
+					ep := subPart(part, t0, t1)
+					e := &bedge{ep, oi, t0, t1, k, util.BoundingBox(ep...), nil}
+					edges = append(edges, e)
+					opEdges[k] = append(opEdges[k], e)
+				}
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return &Shape{}
+	}
+
+	// Find where the edges cross, sweeping in x to limit the pairs tested
+	sorted := make([]*bedge, len(edges))
+	copy(sorted, edges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].bb[0][0] < sorted[j].bb[0][0] })
+	const te = 1e-9
+	for i, e1 := range sorted {
+		for _, e2 := range sorted[i+1:] {
+			if e2.bb[0][0] > e1.bb[1][0] {
+				break
+			}
+			if !util.BBOverlap(e1.bb, e2.bb) {
+				continue
+			}
+			for _, ts := range partIntersections(e1.part, e2.part) {
+				p1, p2 := util.DeCasteljau(e1.part, ts[0]), util.DeCasteljau(e2.part, ts[1])
+				x, y := (p1[0]+p2[0])/2, (p1[1]+p2[1])/2
+				if ts[0] > te && ts[0] < 1-te {
+					e1.splits = append(e1.splits, []float64{ts[0], x, y})
+				}
+				if ts[1] > te && ts[1] < 1-te {
+					e2.splits = append(e2.splits, []float64{ts[1], x, y})
+				}
+			}
+		}
+	}
+
+	// Split the edges into fragments and weld their end points
+	w := newWelder(util.Epsilon)
+	frags := []*bfrag{}
+	for _, e := range edges {
+		sort.Slice(e.splits, func(i, j int) bool { return e.splits[i][0] < e.splits[j][0] })
+		last := e.part[len(e.part)-1]
+		ends := append(e.splits, []float64{1, last[0], last[1]})
+		lt, lpt := 0.0, e.part[0]
+		for _, end := range ends {
+			t := end[0]
+			if t-lt < te {
+				continue
+			}
+			fp := subPart(e.part, lt, t)
+			fp[0] = []float64{lpt[0], lpt[1]}
+			fp[len(fp)-1] = []float64{end[1], end[2]}
+			dt := e.t1 - e.t0
+			frags = append(frags, &bfrag{fp, e.orig, e.t0 + lt*dt, e.t0 + t*dt, w.add(fp[0]), w.add(end[1:]), false})
+			lt, lpt = t, end[1:]
+		}
+	}
+
+	// Keep the fragments that separate inside from outside and orient them
+	kept := []*bfrag{}
+	for _, f := range frags {
+		f.part[0] = w.pts[f.sv]
+		f.part[len(f.part)-1] = w.pts[f.ev]
+		if f.sv == f.ev && bbDiag(f.part) < util.Epsilon {
+			continue
+		}
+		lp, rp := fragSamples(f.part)
+		wl, wr := make([]int, len(shapes)), make([]int, len(shapes))
+		for k := range shapes {
+			wl[k] = windingNumber(lp, opEdges[k])
+			wr[k] = windingNumber(rp, opEdges[k])
+		}
+		il, ir := inside(wl), inside(wr)
+		if il == ir {
+			continue
+		}
+		if ir {
+			f.part = ReversePoints(f.part)
+			f.sv, f.ev = f.ev, f.sv
+			f.rev = true
+		}
+		// Coincident edges from both shapes produce identical fragments
+		mp := util.DeCasteljau(f.part, 0.5)
+		dup := false
+		for _, g := range kept {
+			if g.sv == f.sv && g.ev == f.ev &&
+				util.DistanceESquared(util.DeCasteljau(g.part, 0.5), mp) < util.Epsilon {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			kept = append(kept, f)
+		}
+	}
+
+	return linkFragments(kept, origs, w)
+}
+
+// closedParts returns the parts of a path, adding the closing line if the path is open.
+func closedParts(path *Path) []Part {
+	parts := path.Parts()
+	if len(parts) == 0 || len(parts[0]) == 1 {
+		return nil
+	}
+	last := parts[len(parts)-1]
+	s, e := parts[0][0], last[len(last)-1]
+	if !util.EqualsP(s, e) {
+		parts = append(parts, Part{e, s})
+	}
+	return parts
+}
+
+// partDegenerate returns true if all the part's points are coincident.
+func partDegenerate(part Part) bool {
+	for _, pt := range part[1:] {
+		if !util.EqualsP(pt, part[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fragSamples returns two points either side of the mid-point of the part, left then right.
+func fragSamples(part Part) ([]float64, []float64) {
+	mp := util.DeCasteljau(part, 0.5)
+	dx, dy := unit(mp[2], mp[3])
+	if dx == 0 && dy == 0 {
+		dx, dy = unit(part[len(part)-1][0]-part[0][0], part[len(part)-1][1]-part[0][1])
+	}
+	l := util.DistanceE(part[0], part[len(part)-1])
+	eps := math.Min(math.Max(l*1e-3, 1e-7), 1e-3)
+	nx, ny := -dy*eps, dx*eps
+	return []float64{mp[0] + nx, mp[1] + ny}, []float64{mp[0] - nx, mp[1] - ny}
+}
+
+// windingNumber returns the winding number of the edges about pt. Edges that
+// run in the direction of increasing y, to the right of pt, count +1, so paths with a positive
+// area (see Path.Area) have a positive winding number.
+func windingNumber(pt []float64, edges []*bedge) int {
+	x, y := pt[0], pt[1]
+	sum := 0
+	for _, e := range edges {
+		bb := e.bb
+		if y < bb[0][1] || y > bb[1][1] || x > bb[1][0] {
+			continue
+		}
+		n := len(e.part) - 1
+		if n == 1 {
+			y0, y1 := e.part[0][1], e.part[1][1]
+			if y0 <= y && y < y1 || y1 <= y && y < y0 {
+				t := (y - y0) / (y1 - y0)
+				if util.Lerp(t, e.part[0][0], e.part[1][0]) > x {
+					if y1 > y0 {
+						sum++
+					} else {
+						sum--
+					}
+				}
+			}
+			continue
+		}
+		ys := util.BezierY(e.part)
+		for i := range ys {
+			ys[i] -= y
+		}
+		// As with lines, an end point on the ray only counts if it's the lower end of the curve there,
+		// i.e. the curve leaves the start upward or arrives at the end from above
+		if ys[0] == 0 && e.part[0][0] > x && firstNonZero(ys) > 0 {
+			sum++
+		}
+		if ys[n] == 0 && e.part[n][0] > x && lastNonZero(ys) > 0 {
+			sum--
+		}
+		dys := util.BernsteinDerivative(ys)
+		xs := util.BezierX(e.part)
+		for _, t := range util.BernsteinRoots(ys) {
+			if ys[0] == 0 && t < 1e-9 || ys[n] == 0 && t > 1-1e-9 {
+				// Handled above
+				continue
+			}
+			if t < 0 || t > 1 || util.BernsteinEval(xs, t) <= x {
+				continue
+			}
+			dy := util.BernsteinEval(dys, t)
+			if dy > 0 {
+				sum++
+			} else if dy < 0 {
+				sum--
+			}
+		}
+	}
+	return sum
+}
+
+// firstNonZero returns the first non-zero value in vs, or 0 if there isn't one.
+func firstNonZero(vs []float64) float64 {
+	for _, v := range vs {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// lastNonZero returns the last non-zero value in vs, or 0 if there isn't one.
+func lastNonZero(vs []float64) float64 {
+	for i := len(vs) - 1; i >= 0; i-- {
+		if vs[i] != 0 {
+			return vs[i]
+		}
+	}
+	return 0
+}
+
+// linkFragments joins the fragments end to end into closed paths. Where there's a choice at a
+// vertex, the fragment that turns most to the left is taken. Consecutive fragments from the
+// same original part are merged back into a single part.
+func linkFragments(frags []*bfrag, origs []Part, w *welder) *Shape {
+	out := make(map[int][]int)
+	for i, f := range frags {
+		out[f.sv] = append(out[f.sv], i)
+	}
+	used := make([]bool, len(frags))
+	res := &Shape{}
+	for i, f := range frags {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		loop := []*bfrag{f}
+		cur := f
+		for cur.ev != f.sv {
+			best, ba := -1, -math.MaxFloat64
+			din := util.DeCasteljau(cur.part, 1)
+			for _, j := range out[cur.ev] {
+				if used[j] {
+					continue
+				}
+				dout := util.DeCasteljau(frags[j].part, 0)
+				a := math.Atan2(din[2]*dout[3]-din[3]*dout[2], din[2]*dout[2]+din[3]*dout[3])
+				if a > ba {
+					best, ba = j, a
+				}
+			}
+			if best < 0 {
+				break
+			}
+			used[best] = true
+			cur = frags[best]
+			loop = append(loop, cur)
+		}
+		parts := mergeFragments(loop, origs, w)
+		path := PartsToPath(parts...)
+		if path == nil {
+			continue
+		}
+		res.AddPaths(path.Close())
+	}
+	return res
+}
+
+func mergeFragments(loop []*bfrag, origs []Part, w *welder) []Part {
+	joins := func(a, b *bfrag) bool {
+		if a.orig != b.orig || a.rev != b.rev {
+			return false
+		}
+		if a.rev {
+			return a.t0 == b.t1
+		}
+		return a.t1 == b.t0
+	}
+
+	// Rotate the loop so it doesn't start in the middle of a run
+	n := len(loop)
+	for i := 0; i < n && joins(loop[n-1], loop[0]); i++ {
+		loop = append(loop[1:], loop[0])
+	}
+
+	parts := []Part{}
+	for i := 0; i < n; {
+		f := loop[i]
+		t0, t1, ev := f.t0, f.t1, f.ev
+		j := i + 1
+		for ; j < n && joins(loop[j-1], loop[j]); j++ {
+			if f.rev {
+				t0 = loop[j].t0
+			} else {
+				t1 = loop[j].t1
+			}
+			ev = loop[j].ev
+		}
+		part := f.part
+		if j > i+1 {
+			part = subPart(origs[f.orig], t0, t1)
+			if f.rev {
+				part = ReversePoints(part)
+			}
+			part[0] = w.pts[f.sv]
+			part[len(part)-1] = w.pts[ev]
+		}
+		parts = append(parts, part)
+		i = j
+	}
+	return parts
+}
+
+// welder maps points within tol of each other to the same vertex.
+type welder struct {
+	tol   float64
+	cells map[[2]int64][]int
+	pts   [][]float64
+}
+
+func newWelder(tol float64) *welder {
+	return &welder{tol, make(map[[2]int64][]int), nil}
+}
+
+func (w *welder) add(pt []float64) int {
+	cx, cy := int64(math.Floor(pt[0]/w.tol)), int64(math.Floor(pt[1]/w.tol))
+	t2 := w.tol * w.tol
+	for dx := int64(-1); dx < 2; dx++ {
+		for dy := int64(-1); dy < 2; dy++ {
+			for _, id := range w.cells[[2]int64{cx + dx, cy + dy}] {
+				if util.DistanceESquared(w.pts[id], pt) <= t2 {
+					return id
+				}
+			}
+		}
+	}
+	id := len(w.pts)
+	w.pts = append(w.pts, []float64{pt[0], pt[1]})
+	key := [2]int64{cx, cy}
+	w.cells[key] = append(w.cells[key], id)
+	return id
+}
@@ -0,0 +1,43 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+	"github.com/jphsd/graphics2d/image"
+)
+
+// Demonstrates the four boolean operations on a circle and a square.
+func ExampleBooleanShapes() {
+	circle := g2d.NewShape(g2d.Circle([]float64{90, 100}, 60))
+	square := g2d.NewShape(g2d.Rectangle([]float64{150, 100}, 100, 100))
+
+	img := image.NewRGBA(1000, 200, color.White)
+	ops := []g2d.BooleanOp{g2d.BoolUnion, g2d.BoolIntersect, g2d.BoolDifference, g2d.BoolXor}
+	n := make([]int, len(ops))
+	for i, op := range ops {
+		xfm := g2d.Translate(float64(i*250), 0)
+		shape := g2d.BooleanShapes(op, circle, square).Transform(xfm)
+		g2d.FillShape(img, shape, g2d.RedPen)
+		g2d.DrawShape(img, shape, g2d.BlackPen)
+		n[i] = len(shape.Paths())
+	}
+	image.SaveImage(img, "boolean")
+
+	fmt.Printf("Paths %v, see boolean.png", n)
+	// Output: Paths [1 1 1 2], see boolean.png
+}
+
+// Demonstrates combining a circle with itself. The outlines overlap along their whole length, so the
+// union is the circle and the difference is empty.
+func ExampleShape_Union() {
+	circle := g2d.NewShape(g2d.Circle([]float64{0, 0}, 100))
+
+	union := circle.Union(circle)
+	diff := circle.Difference(circle)
+	fmt.Printf("union %d paths, area %.2f\n", len(union.Paths()), union.Area())
+	fmt.Printf("difference %d paths\n", len(diff.Paths()))
+	// Output:
+	// union 1 paths, area 31416.06
+	// difference 0 paths
+}
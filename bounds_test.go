@@ -0,0 +1,54 @@
+package graphics2d_test
+
+import (
+	"bytes"
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+	"github.com/jphsd/graphics2d/image"
+)
+
+// Demonstrates the difference between the bounding box, which includes the control points, and the
+// tight bounding box of a path.
+func ExamplePath_TightBoundingBox() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{50, 100}, []float64{100, 0})
+	path.AddStep([]float64{150, -100}, []float64{250, 100}, []float64{200, 0})
+
+	fmt.Println(path.BoundingBox(), path.Bounds())
+	fmt.Printf("%.2f %v\n", path.TightBoundingBox(), path.TightBounds())
+	// Output:
+	// [[0 -100] [250 100]] (0,-100)-(250,100)
+	// [[0.00 -28.87] [213.63 50.00]] (0,-29)-(214,50)
+}
+
+// Demonstrates a renderable using the tight bounds of its shapes, so the image it renders to fits the
+// curve rather than its control points.
+func ExampleRenderable_Bounds() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{50, 100}, []float64{100, 0})
+	rend := g2d.NewRenderable(g2d.NewShape(path), g2d.RedPen.Filler, nil)
+	fmt.Println(rend.Bounds())
+	rend.TightBounds = true
+	fmt.Println(rend.Bounds(), rend.Image().Bounds())
+	// Output:
+	// (0,0)-(100,100)
+	// (0,0)-(100,50) (0,0)-(100,50)
+}
+
+// Demonstrates rendering a shape with the rasterizer sized from its tight bounds. The result is the
+// same as with RenderShape.
+func ExampleRenderShapeTight() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{50, 100}, []float64{100, 0})
+	path.Close()
+	shape := g2d.NewShape(path)
+
+	img1 := image.NewRGBA(100, 100, color.White)
+	g2d.RenderShape(img1, shape, image.Black)
+	img2 := image.NewRGBA(100, 100, color.White)
+	g2d.RenderShapeTight(img2, shape, image.Black)
+	fmt.Println(bytes.Equal(img1.Pix, img2.Pix))
+	// Output:
+	// true
+}
@@ -0,0 +1,231 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Dashed stroking with SVG/PDF stroke-dasharray semantics. Unlike DashProc, which flattens the path
+// and cuts it into pieces for a following stroke, the dashes are cut from the path at their exact
+// arc lengths, preserving curves and any corners within them, and each is then stroked on its own.
+// So each dash carries the stroke's caps and the joins within it. On a closed path, a dash that
+// spans the start of the path is stroked as one dash.
+
+// DashedStrokeProc contains the dash pattern, offset and the stroke path processor applied to each
+// dash. The dash pattern represents lengths of pen down, pen up, ... and is in the same coordinate
+// system as the path. If the pattern is odd in length, then it is repeated to make it even, as in
+// SVG. If it contains negative lengths or sums to 0, then the path is stroked undashed.
+//
+// If Adjust is set, the offset is ignored and the pattern is scaled separately for each segment of
+// the path between corners so that the segment starts and ends on a dash. Dashes are centered on
+// the corners, where they're joined, and open paths start and end with a complete dash.
+type DashedStrokeProc struct {
+	Pattern []float64
+	Offset  float64
+	Adjust  bool
+	Stroke  PathProcessor
+}
+
+// dotLength is the length of the dash used to stroke zero length dashes, so that round and square
+// caps still produce dots oriented along the path.
+const dotLength = 1e-3
+
+// NewDashedStrokeProc creates a new dashed stroke path processor with the supplied pattern, offset
+// and stroke path processor, e.g. a StrokeProc with the desired join and cap functions.
+func NewDashedStrokeProc(pattern []float64, offs float64, stroke PathProcessor) DashedStrokeProc {
+	return DashedStrokeProc{pattern, offs, false, stroke}
+}
+
+// Process implements the PathProcessor interface.
+func (dp DashedStrokeProc) Process(p *Path) []*Path {
+	pat := dashPattern(dp.Pattern)
+	if pat == nil || len(p.Steps()) == 1 {
+		return dp.Stroke.Process(p)
+	}
+	l := p.ArcLength()
+	if util.Equals(l, 0) {
+		return dp.Stroke.Process(p)
+	}
+
+	var dashes [][]float64
+	if dp.Adjust {
+		dashes = adjustedDashes(p, pat, l)
+	} else {
+		dashes = dashIntervals(pat, 1, dp.Offset, 0, l)
+	}
+	dashes = mergeDashes(dashes, l, p.Closed())
+
+	res := []*Path{}
+	for _, dash := range dashes {
+		if dash[1]-dash[0] >= l {
+			// Dash covers the entire path
+			return dp.Stroke.Process(p)
+		}
+		res = append(res, dp.Stroke.Process(dashPath(p, dash[0], dash[1], l))...)
+	}
+	return res
+}
+
+// dashPattern returns the pattern, repeated to make it even in length, or nil if it's invalid.
+func dashPattern(pattern []float64) []float64 {
+	sum := 0.0
+	for _, v := range pattern {
+		if v < 0 {
+			return nil
+		}
+		sum += v
+	}
+	if util.Equals(sum, 0) {
+		return nil
+	}
+	pat := append([]float64{}, pattern...)
+	if len(pat)%2 == 1 {
+		pat = append(pat, pattern...)
+	}
+	return pat
+}
+
+// dashIntervals returns the start and end of each dash from s to s + l when the pattern, scaled by
+// scale, is started at phase, a distance into the scaled pattern.
+func dashIntervals(pat []float64, scale, phase, s, l float64) [][]float64 {
+	period := sum(pat) * scale
+	phase = math.Mod(phase, period)
+	if phase < 0 {
+		phase += period
+	}
+
+	// Find where in the pattern phase falls
+	i := 0
+	for phase > 0 && phase >= pat[i]*scale {
+		phase -= pat[i] * scale
+		i = (i + 1) % len(pat)
+	}
+
+	res := [][]float64{}
+	cur, end := s, s+l
+	rem := pat[i]*scale - phase
+	// A zero length dash at the very end is still included
+	for cur < end || (i%2 == 0 && rem == 0 && cur == end) {
+		next := math.Min(cur+rem, end)
+		if i%2 == 0 {
+			res = append(res, []float64{cur, next})
+		}
+		cur = next
+		i = (i + 1) % len(pat)
+		rem = pat[i] * scale
+	}
+	return res
+}
+
+// adjustedDashes returns the dashes for the path with the pattern scaled to fit each segment of the
+// path between corners. Each segment is given a whole number of periods of the pattern, starting and
+// ending in the middle of the first dash, or at the start or end of it at the ends of an open path.
+func adjustedDashes(p *Path, pat []float64, l float64) [][]float64 {
+	corners := pathCorners(p)
+	closed := p.Closed()
+	if closed && len(corners) == 0 {
+		// A smooth closed path is a single segment with no ends
+		corners = []float64{0}
+	}
+	if !closed {
+		corners = append(append([]float64{0}, corners...), l)
+	} else {
+		// Wrap the last segment around to the first corner
+		corners = append(corners, corners[0]+l)
+	}
+
+	period, d := sum(pat), pat[0]
+	res := [][]float64{}
+	for i := 0; i < len(corners)-1; i++ {
+		s, e := corners[i], corners[i+1]
+		sl := e - s
+		if util.Equals(sl, 0) {
+			continue
+		}
+		// Open path ends are the start of a dash, not the middle of one
+		startEnd, endEnd := !closed && i == 0, !closed && i == len(corners)-2
+		extra := 0.0
+		if startEnd {
+			extra += d / 2
+		}
+		if endEnd {
+			extra += d / 2
+		}
+		k := math.Max(0, math.Round((sl-extra)/period))
+		if k == 0 && util.Equals(extra, 0) {
+			k = 1
+		}
+		scale := sl / (k*period + extra)
+		phase := d / 2 * scale
+		if startEnd {
+			phase = 0
+		}
+		res = append(res, dashIntervals(pat, scale, phase, s, sl)...)
+	}
+	return res
+}
+
+// pathCorners returns the distances along the path of the points where its tangent is
+// discontinuous, including the start of a closed path.
+func pathCorners(p *Path) []float64 {
+	at := p.arcLengths()
+	parts := at.parts
+	n := len(parts)
+	res := []float64{}
+	for i := range n {
+		if i == 0 && !p.Closed() {
+			continue
+		}
+		prev := parts[(i+n-1)%n]
+		px, py := partTangent(prev, 1)
+		nx, ny := partTangent(parts[i], 0)
+		if px*nx+py*ny < 1-util.Epsilon {
+			res = append(res, at.starts[i])
+		}
+	}
+	return res
+}
+
+// mergeDashes joins dashes that meet, such as those either side of a corner when the pattern has
+// been adjusted, and, for closed paths, the dashes either side of the start of the path. Dashes
+// that would start at or after the end of a closed path are wrapped.
+func mergeDashes(dashes [][]float64, l float64, closed bool) [][]float64 {
+	res := make([][]float64, 0, len(dashes))
+	for _, dash := range dashes {
+		if n := len(res); n > 0 && util.Equals(res[n-1][1], dash[0]) && dash[1] > dash[0] {
+			res[n-1] = []float64{res[n-1][0], dash[1]}
+			continue
+		}
+		res = append(res, dash)
+	}
+	if !closed || len(res) < 2 {
+		return res
+	}
+	first, last := res[0], res[len(res)-1]
+	if util.Equals(first[0]+l, last[1]) || (util.Equals(first[0], 0) && util.Equals(last[1], l)) {
+		// Dash spans the start of the path
+		res[0] = []float64{last[0], last[1] + first[1] - first[0]}
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
+// dashPath returns the section of the path from distance s to e along it. Distances beyond the end
+// of a closed path are wrapped to its start.
+func dashPath(p *Path, s, e, l float64) *Path {
+	if s >= l && p.Closed() {
+		s, e = s-l, e-l
+	}
+	if util.Equals(s, e) {
+		// Zero length dash
+		pt, tang := p.PointAtLength(s), p.TangentAtLength(s)
+		dx, dy := tang[0]*dotLength/2, tang[1]*dotLength/2
+		return Line([]float64{pt[0] - dx, pt[1] - dy}, []float64{pt[0] + dx, pt[1] + dy})
+	}
+	from := p.TAtLength(s)
+	if e > l {
+		return p.SubPath(from, p.TAtLength(e-l))
+	}
+	return p.SubPath(from, p.TAtLength(e))
+}
@@ -0,0 +1,30 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates dashing a closed square, where the dash spanning the start of the path is stroked as a
+// single dash with a join at the corner, and adjusting a pattern so a line starts and ends on a dash.
+func ExampleDashedStrokeProc() {
+	stroke := g2d.NewStrokeProcExt(10, -10, g2d.JoinRound, 0, g2d.CapRound)
+
+	square := g2d.Polygon([]float64{0, 0}, []float64{100, 0}, []float64{100, 100}, []float64{0, 100})
+	dashed := g2d.NewDashedStrokeProc([]float64{30, 20}, 10, stroke)
+	fmt.Printf("square: %d dashes\n", len(square.Process(dashed)))
+
+	line := g2d.Line([]float64{0, 0}, []float64{110, 0})
+	dashed = g2d.NewDashedStrokeProc([]float64{10, 5}, 0, g2d.NewStrokeProcExt(10, -10, g2d.JoinRound, 0, g2d.CapButt))
+	dashes := line.Process(dashed)
+	bb := dashes[len(dashes)-1].BoundingBox()
+	fmt.Printf("line: %d dashes, last from %.2f to %.2f\n", len(dashes), bb[0][0], bb[1][0])
+	dashed.Adjust = true
+	dashes = line.Process(dashed)
+	bb = dashes[len(dashes)-1].BoundingBox()
+	fmt.Printf("adjusted line: %d dashes, last from %.2f to %.2f\n", len(dashes), bb[0][0], bb[1][0])
+	// Output:
+	// square: 8 dashes
+	// line: 8 dashes, last from 105.00 to 110.00
+	// adjusted line: 8 dashes, last from 100.43 to 110.00
+}
@@ -33,7 +33,8 @@ func DrawShape(dst draw.Image, shape *Shape, pen *Pen) {
 		shape = shape.Transform(pen.Xfm)
 	}
 	if pen.Stroke != nil {
-		shape = shape.ProcessPaths(pen.Stroke)
+		// Stroke outlines overlap themselves so are always filled NonZero
+		shape = shape.ProcessPaths(pen.Stroke).SetFillRule(NonZero)
 	}
 	RenderShape(dst, shape, pen.Filler)
 }
@@ -44,7 +45,7 @@ func DrawClippedShape(dst draw.Image, shape, clip *Shape, pen *Pen) {
 		shape = shape.Transform(pen.Xfm)
 	}
 	if pen.Stroke != nil {
-		shape = shape.ProcessPaths(pen.Stroke)
+		shape = shape.ProcessPaths(pen.Stroke).SetFillRule(NonZero)
 	}
 	RenderClippedShape(dst, shape, clip, pen.Filler)
 }
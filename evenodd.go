@@ -0,0 +1,112 @@
+package graphics2d
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// golang.org/x/image/vector accumulates signed area and so can only render with the non-zero
+// winding rule. Shapes with the EvenOdd fill rule are rendered by this scanline rasterizer instead.
+// Each pixel row is sampled by evenOddSamples sub-scanlines and the spans between alternate edge
+// crossings on a sub-scanline contribute their exact horizontal coverage.
+
+// evenOddSamples is the number of sub-scanlines per pixel row.
+const evenOddSamples = 16
+
+type eoEdge struct {
+	x0, y0, x1, y1 float64 // y0 < y1
+}
+
+// renderEvenOdd returns an Alpha image with bounds drect containing the coverage of the paths
+// using the even-odd rule.
+func renderEvenOdd(paths []*Path, drect image.Rectangle) *image.Alpha {
+	res := image.NewAlpha(drect)
+	w, h := drect.Dx(), drect.Dy()
+	minx, miny := float64(drect.Min.X), float64(drect.Min.Y)
+
+	// Collect the edges, as they would be for the vector rasterizer, bucketed by the rows they cross
+	rows := make([][]int, h)
+	edges := []eoEdge{}
+	addEdge := func(p0, p1 []float64) {
+		x0, y0, x1, y1 := p0[0]-minx, p0[1]-miny, p1[0]-minx, p1[1]-miny
+		if y0 == y1 {
+			return
+		}
+		if y0 > y1 {
+			x0, y0, x1, y1 = x1, y1, x0, y0
+		}
+		r0, r1 := max(int(math.Floor(y0)), 0), min(int(math.Ceil(y1)), h)
+		if r0 >= r1 {
+			return
+		}
+		ei := len(edges)
+		edges = append(edges, eoEdge{x0, y0, x1, y1})
+		for r := r0; r < r1; r++ {
+			rows[r] = append(rows[r], ei)
+		}
+	}
+	for _, path := range paths {
+		prect := drect.Intersect(path.Bounds())
+		if prect.Empty() {
+			continue
+		}
+		fp := path.Flatten(RenderFlatten)
+		steps := fp.steps
+		for i := 1; i < len(steps); i++ {
+			addEdge(steps[i-1][0], steps[i][0])
+		}
+		// Force closed
+		addEdge(steps[len(steps)-1][0], steps[0][0])
+	}
+
+	acc := make([]float64, w+1)
+	diff := make([]float64, w+2)
+	xs := []float64{}
+	fw := float64(w)
+	for r := range h {
+		if len(rows[r]) == 0 {
+			continue
+		}
+		clear(acc)
+		clear(diff)
+		for s := range evenOddSamples {
+			sy := float64(r) + (float64(s)+0.5)/evenOddSamples
+			xs = xs[:0]
+			for _, ei := range rows[r] {
+				e := edges[ei]
+				if sy < e.y0 || sy >= e.y1 {
+					continue
+				}
+				t := (sy - e.y0) / (e.y1 - e.y0)
+				xs = append(xs, e.x0+t*(e.x1-e.x0))
+			}
+			sort.Float64s(xs)
+			for i := 1; i < len(xs); i += 2 {
+				// Span [xa, xb) clipped to [0, w)
+				xa, xb := max(xs[i-1], 0), min(xs[i], fw)
+				if xa >= xb {
+					continue
+				}
+				ia, ib := int(xa), int(xb)
+				if ia == ib {
+					acc[ia] += xb - xa
+					continue
+				}
+				acc[ia] += float64(ia+1) - xa
+				diff[ia+1]++
+				diff[ib]--
+				acc[ib] += xb - float64(ib)
+			}
+		}
+		run := 0.0
+		off := res.PixOffset(drect.Min.X, drect.Min.Y+r)
+		for x := range w {
+			run += diff[x]
+			c := (acc[x] + run) / evenOddSamples
+			res.Pix[off+x] = uint8(min(c*0xff+0.5, 0xff))
+		}
+	}
+
+	return res
+}
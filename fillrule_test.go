@@ -0,0 +1,64 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+	"github.com/jphsd/graphics2d/image"
+)
+
+// Demonstrates the difference between the NonZero and EvenOdd fill rules using a pentagram
+// and two nested squares with the same orientation.
+func ExampleShape_SetFillRule() {
+	pts := g2d.RegularPolygon(5, []float64{100, 110}, 110, 0).Steps()
+	star := g2d.Polygon(pts[0][0], pts[2][0], pts[4][0], pts[1][0], pts[3][0])
+	squares := []*g2d.Path{
+		g2d.Rectangle([]float64{300, 100}, 160, 160),
+		g2d.Rectangle([]float64{300, 100}, 80, 80)}
+
+	img := image.NewRGBA(800, 200, color.White)
+	for i, rule := range []g2d.FillRule{g2d.NonZero, g2d.EvenOdd} {
+		shape := g2d.NewShape(star).SetFillRule(rule)
+		shape.AddPaths(squares...)
+		shape = shape.Transform(g2d.Translate(float64(i*400), 0))
+		g2d.FillShape(img, shape, g2d.RedPen)
+		fmt.Printf("%s %v %v, ", rule, shape.Contains([]float64{float64(i*400) + 100, 100}),
+			shape.PointInShape([]float64{float64(i*400) + 300, 100}))
+	}
+	image.SaveImage(img, "fillrule")
+
+	fmt.Printf("see fillrule.png")
+	// Output: nonzero true true, evenodd false false, see fillrule.png
+}
+
+// Demonstrates that the outline of a self-overlapping stroke is filled the same whatever the fill
+// rule of the shape being stroked.
+func ExampleShape_SetFillRule_stroke() {
+	hairpin := g2d.PolyLine([]float64{20, 50}, []float64{180, 50}, []float64{20, 60})
+	pen := g2d.NewStrokedPen(color.Black, 30, g2d.JoinRound, nil)
+
+	for _, rule := range []g2d.FillRule{g2d.NonZero, g2d.EvenOdd} {
+		img := image.NewRGBA(200, 110, color.White)
+		g2d.DrawShape(img, g2d.NewShape(hairpin).SetFillRule(rule), pen)
+		n := 0
+		for y := range 110 {
+			for x := range 200 {
+				if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+					n++
+				}
+			}
+		}
+		fmt.Printf("%s %d, ", rule, n)
+	}
+	// Output: nonzero 3280, evenodd 3280,
+}
+
+// Demonstrates testing a point against a path with an explicit fill rule. The center of a pentagram
+// has a winding number of 2.
+func ExamplePath_PointInPathRule() {
+	pts := g2d.RegularPolygon(5, []float64{100, 110}, 110, 0).Steps()
+	star := g2d.Polygon(pts[0][0], pts[2][0], pts[4][0], pts[1][0], pts[3][0])
+	center := []float64{100, 110}
+	fmt.Println(star.PointInPathRule(center, g2d.NonZero), star.PointInPathRule(center, g2d.EvenOdd))
+	// Output: true false
+}
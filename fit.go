@@ -0,0 +1,300 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Curve fitting using the algorithm from Schneider's "An Algorithm for Automatically Fitting Digitized
+// Curves" (Graphics Gems). The points are first broken into runs at corners. A cubic is fitted to each
+// run by least squares, with its end tangents fixed, using a chord length parameterization that is then
+// improved by Newton-Raphson iteration. If the fit isn't within the tolerance, the run is split at the
+// point of maximum error and each half fitted in turn.
+
+// FitCornerAngle is the minimum change in direction, in radians, at a point for it to be treated as a
+// corner by FitCurve.
+var FitCornerAngle = math.Pi / 3
+
+// maxFitIterations is the number of reparameterizations tried before a run is split.
+const maxFitIterations = 4
+
+// FitCurve returns a path of cubic steps, with lines for runs of two points, that passes within tol
+// of the supplied points.
+func FitCurve(tol float64, pts ...[]float64) *Path {
+	return fitCurve(tol, false, pts)
+}
+
+// FitClosedCurve is the same as FitCurve but treats the points as a closed loop and returns a
+// closed path.
+func FitClosedCurve(tol float64, pts ...[]float64) *Path {
+	return fitCurve(tol, true, pts)
+}
+
+func fitCurve(tol float64, closed bool, pts [][]float64) *Path {
+	// Remove coincident points
+	dpts := make([][]float64, 0, len(pts))
+	for _, pt := range pts {
+		if len(dpts) == 0 || !util.EqualsP(dpts[len(dpts)-1], pt) {
+			dpts = append(dpts, []float64{pt[0], pt[1]})
+		}
+	}
+	if closed && len(dpts) > 1 && util.EqualsP(dpts[0], dpts[len(dpts)-1]) {
+		dpts = dpts[:len(dpts)-1]
+	}
+	n := len(dpts)
+	if n == 0 {
+		return nil
+	}
+	if n < 3 {
+		res := PolyLine(dpts...)
+		if closed {
+			res.Close()
+		}
+		return res
+	}
+
+	// Find the corners
+	corners := []int{}
+	for i := range n {
+		if !closed && (i == 0 || i == n-1) {
+			continue
+		}
+		in, out := fitDirection(dpts, closed, i, -1, tol), fitDirection(dpts, closed, i, 1, tol)
+		if dot(in, out) > -math.Cos(FitCornerAngle) {
+			corners = append(corners, i)
+		}
+	}
+
+	// Form the runs between corners
+	runs := [][][]float64{}
+	if closed {
+		start := 0
+		if len(corners) > 0 {
+			start = corners[0]
+		}
+		loop := append(append([][]float64{}, dpts[start:]...), dpts[:start+1]...)
+		prev := 0
+		for _, c := range corners[min(1, len(corners)):] {
+			c = (c - start + n) % n
+			runs = append(runs, loop[prev:c+1])
+			prev = c
+		}
+		runs = append(runs, loop[prev:])
+	} else {
+		prev := 0
+		for _, c := range corners {
+			runs = append(runs, dpts[prev:c+1])
+			prev = c
+		}
+		runs = append(runs, dpts[prev:])
+	}
+
+	res := NewPath(runs[0][0])
+	for i, run := range runs {
+		nr := len(run)
+		if nr == 2 {
+			res.AddStep(run[1])
+			continue
+		}
+		t0, t1 := fitDirection(run, false, 0, 1, tol), fitDirection(run, false, nr-1, -1, tol)
+		if closed && len(corners) == 0 && i == 0 {
+			// No corner at the start, so use the direction across it for both ends
+			t0 = unitVec(run[nr-2], run[1])
+			t1 = []float64{-t0[0], -t0[1]}
+		}
+		for _, part := range fitCubics(run, t0, t1, tol, nil) {
+			res.AddStep(part[1:]...)
+		}
+	}
+	if closed {
+		res.Close()
+	}
+	return res
+}
+
+// fitDirection returns the unit direction from point i to the first point in direction dir that's
+// further than 2 * tol away from it.
+func fitDirection(pts [][]float64, closed bool, i, dir int, tol float64) []float64 {
+	n := len(pts)
+	j := i
+	for {
+		j += dir
+		if closed {
+			j = (j + n) % n
+		} else if j < 0 || j >= n {
+			j -= dir
+			break
+		}
+		if j == i || util.DistanceE(pts[i], pts[j]) > 2*tol {
+			break
+		}
+	}
+	return unitVec(pts[i], pts[j])
+}
+
+// dot returns the dot product of the two vectors.
+func dot(a, b []float64) float64 {
+	return a[0]*b[0] + a[1]*b[1]
+}
+
+// unitVec returns the normalized vector from a to b.
+func unitVec(a, b []float64) []float64 {
+	dx, dy := unit(b[0]-a[0], b[1]-a[1])
+	return []float64{dx, dy}
+}
+
+// fitCubics appends the cubics fitted to the points, with start and end tangents t0 and t1, to res.
+func fitCubics(pts [][]float64, t0, t1 []float64, tol float64, res []Part) []Part {
+	n := len(pts)
+	if n == 2 {
+		d := util.DistanceE(pts[0], pts[1]) / 3
+		return append(res, fitPart(pts[0], pts[1], t0, t1, d, d))
+	}
+
+	u := chordParams(pts)
+	part := fitGenerate(pts, u, t0, t1)
+	err, split := fitError(pts, part, u)
+	if err < tol*tol {
+		return append(res, part)
+	}
+	if err < 4*tol*tol {
+		// Close, so try improving the parameterization
+		for range maxFitIterations {
+			u = fitReparameterize(pts, part, u)
+			part = fitGenerate(pts, u, t0, t1)
+			err, split = fitError(pts, part, u)
+			if err < tol*tol {
+				return append(res, part)
+			}
+		}
+	}
+
+	// Split at the point of maximum error and fit the halves
+	tc := unitVec(pts[split+1], pts[split-1])
+	res = fitCubics(pts[:split+1], t0, tc, tol, res)
+	return fitCubics(pts[split:], []float64{-tc[0], -tc[1]}, t1, tol, res)
+}
+
+// fitPart returns the cubic from p0 to p3 with control points a0 along t0 and a1 along t1.
+func fitPart(p0, p3, t0, t1 []float64, a0, a1 float64) Part {
+	return Part{
+		p0,
+		{p0[0] + t0[0]*a0, p0[1] + t0[1]*a0},
+		{p3[0] + t1[0]*a1, p3[1] + t1[1]*a1},
+		p3,
+	}
+}
+
+// chordParams returns t values for the points proportional to the distance along them.
+func chordParams(pts [][]float64) []float64 {
+	n := len(pts)
+	u := make([]float64, n)
+	for i := 1; i < n; i++ {
+		u[i] = u[i-1] + util.DistanceE(pts[i-1], pts[i])
+	}
+	for i := range u {
+		u[i] /= u[n-1]
+	}
+	return u
+}
+
+// fitGenerate finds the distances of the control points along the tangents that minimize the squared
+// error between the cubic at u and the points.
+func fitGenerate(pts [][]float64, u []float64, t0, t1 []float64) Part {
+	n := len(pts)
+	p0, p3 := pts[0], pts[n-1]
+	var c00, c01, c11, x0, x1 float64
+	for i, t := range u {
+		mt := 1 - t
+		b0, b1, b2, b3 := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		a0 := []float64{t0[0] * b1, t0[1] * b1}
+		a1 := []float64{t1[0] * b2, t1[1] * b2}
+		c00 += dot(a0, a0)
+		c01 += dot(a0, a1)
+		c11 += dot(a1, a1)
+		tmp := []float64{
+			pts[i][0] - (b0+b1)*p0[0] - (b2+b3)*p3[0],
+			pts[i][1] - (b0+b1)*p0[1] - (b2+b3)*p3[1],
+		}
+		x0 += dot(a0, tmp)
+		x1 += dot(a1, tmp)
+	}
+
+	det := c00*c11 - c01*c01
+	a0, a1 := 0.0, 0.0
+	if det != 0 {
+		a0, a1 = (x0*c11-x1*c01)/det, (c00*x1-c01*x0)/det
+	}
+
+	// Fall back to the Wu/Barsky heuristic if the solution is degenerate
+	seg := util.DistanceE(p0, p3)
+	eps := 1e-6 * seg
+	if a0 < eps || a1 < eps {
+		a0, a1 = seg/3, seg/3
+	}
+	return fitPart(p0, p3, t0, t1, a0, a1)
+}
+
+// fitError returns the maximum squared distance of the points from the cubic at their t values and
+// the index of the point where it occurs.
+func fitError(pts [][]float64, part Part, u []float64) (float64, int) {
+	n := len(pts)
+	md, split := 0.0, n/2
+	for i := 1; i < n-1; i++ {
+		d := util.DistanceESquared(util.DeCasteljau(part, u[i])[:2], pts[i])
+		if d >= md {
+			md, split = d, i
+		}
+	}
+	return md, split
+}
+
+// fitReparameterize improves the t values of the points with a Newton-Raphson step.
+func fitReparameterize(pts [][]float64, part Part, u []float64) []float64 {
+	d1 := toDerivative(part)
+	d2 := toDerivative(d1)
+	res := make([]float64, len(u))
+	for i, t := range u {
+		q, q1, q2 := util.DeCasteljau(part, t), util.DeCasteljau(d1, t), util.DeCasteljau(d2, t)
+		dx, dy := q[0]-pts[i][0], q[1]-pts[i][1]
+		num := dx*q1[0] + dy*q1[1]
+		den := q1[0]*q1[0] + q1[1]*q1[1] + dx*q2[0] + dy*q2[1]
+		if den == 0 {
+			res[i] = t
+			continue
+		}
+		res[i] = clamp01(t - num/den)
+	}
+	return res
+}
+
+// toDerivative returns the control points of the derivative of the part.
+func toDerivative(part Part) Part {
+	n := len(part) - 1
+	res := make(Part, n)
+	for i := range n {
+		res[i] = []float64{float64(n) * (part[i+1][0] - part[i][0]), float64(n) * (part[i+1][1] - part[i][1])}
+	}
+	return res
+}
+
+// FitProc replaces a path with a path of cubics fitted to its flattened points within Tolerance.
+type FitProc struct {
+	Tolerance float64
+}
+
+// Process implements the PathProcessor interface.
+func (fp FitProc) Process(p *Path) []*Path {
+	pts, closed := p.PolyLine()
+	var res *Path
+	if closed {
+		res = FitClosedCurve(fp.Tolerance, pts...)
+	} else {
+		res = FitCurve(fp.Tolerance, pts...)
+	}
+	if res == nil {
+		return []*Path{p.Copy()}
+	}
+	return []*Path{res}
+}
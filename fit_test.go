@@ -0,0 +1,30 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"math"
+)
+
+// Demonstrates fitting cubics to noisy points sampled from a semicircle followed by a line, and
+// re-curving a flattened circle.
+func ExampleFitCurve() {
+	pts := [][]float64{}
+	for i := 0; i <= 100; i++ {
+		a := float64(i) / 100 * math.Pi
+		noise := 0.25 * math.Sin(float64(i)*7)
+		pts = append(pts, []float64{(100 + noise) * math.Cos(a), (100 + noise) * math.Sin(a)})
+	}
+	for i := 1; i <= 20; i++ {
+		pts = append(pts, []float64{-100 + float64(i)*10, 0})
+	}
+	path := g2d.FitCurve(1, pts...)
+	fmt.Printf("%d points, %d steps\n", len(pts), len(path.Steps())-1)
+
+	flat := g2d.Circle([]float64{0, 0}, 100).Flatten(0.1)
+	curved := flat.Process(g2d.FitProc{0.5})[0]
+	fmt.Printf("%d lines, %d steps, area %.0f\n", len(flat.Steps())-1, len(curved.Steps())-1, curved.Area())
+	// Output:
+	// 121 points, 4 steps
+	// 128 lines, 8 steps, area 31382
+}
@@ -0,0 +1,150 @@
+package graphics2d
+
+import (
+	"fmt"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font fallback. A font set holds an ordered list of fonts, and each rune of a string is taken from
+// the first font that has a glyph for it. If none of them do, then the first font's notdef glyph is
+// used. Glyphs and advances from fonts with a different units per em to the first font are scaled
+// to it, so the set behaves as a single font in the first font's units. Kerning is only applied
+// between consecutive glyphs from the same font.
+
+// FontSet contains the fonts, in order of preference, and the glyph cache they're loaded through.
+type FontSet struct {
+	Fonts []*sfnt.Font
+	Cache *GlyphCache // If nil, DefaultGlyphCache is used
+}
+
+// NewFontSet creates a new font set from the fonts using DefaultGlyphCache.
+func NewFontSet(fonts ...*sfnt.Font) *FontSet {
+	return &FontSet{fonts, nil}
+}
+
+// UnitsPerEm returns the units per em of the set, i.e. that of the first font.
+func (fs *FontSet) UnitsPerEm() float64 {
+	if len(fs.Fonts) == 0 {
+		return 0
+	}
+	return float64(fs.Fonts[0].UnitsPerEm())
+}
+
+// FontFor returns the font in the set used for rune r, the first font with a glyph for it, or the
+// first font if none of them have one.
+func (fs *FontSet) FontFor(r rune) (*sfnt.Font, error) {
+	_, tfont, _, _, err := fs.glyph(r)
+	return tfont, err
+}
+
+// Glyph returns the shape and advance of rune r from the font set, both in the set's units. The
+// shape is the caller's to modify.
+func (fs *FontSet) Glyph(r rune) (*Shape, float64, error) {
+	shape, _, _, adv, err := fs.glyph(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return shape.Copy(), adv, nil
+}
+
+// glyph returns the shape and advance of rune r, in the set's units, and the font and glyph index
+// it came from. The shape may be shared via the glyph cache, so it must only be copied or
+// transformed into a new shape.
+func (fs *FontSet) glyph(r rune) (*Shape, *sfnt.Font, sfnt.GlyphIndex, float64, error) {
+	if len(fs.Fonts) == 0 {
+		return nil, nil, 0, 0, fmt.Errorf("font set has no fonts")
+	}
+	cache := fs.Cache
+	if cache == nil {
+		cache = DefaultGlyphCache
+	}
+	tfont := fs.Fonts[0]
+	var shape *Shape
+	var gi sfnt.GlyphIndex
+	var adv float64
+	for i, f := range fs.Fonts {
+		ge, err := cache.entry(f, r, float64(f.UnitsPerEm()))
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		if i == 0 {
+			// Notdef, if no font has the rune
+			shape, adv = ge.shape, ge.adv
+		}
+		if ge.gi != 0 {
+			tfont, shape, gi, adv = f, ge.shape, ge.gi, ge.adv
+			break
+		}
+	}
+	if fs.scale(tfont) != 1 {
+		// Use the glyph as scaled and cached for the set's units
+		ge, err := cache.entry(tfont, r, fs.UnitsPerEm())
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		shape, adv = ge.shape, ge.adv
+	}
+	return shape, tfont, gi, adv, nil
+}
+
+// scale returns the scale factor from the font's units to the set's.
+func (fs *FontSet) scale(tfont *sfnt.Font) float64 {
+	return fs.UnitsPerEm() / float64(tfont.UnitsPerEm())
+}
+
+// StringToShape returns the string rendered as both a single shape, and as individual shapes,
+// correctly offset in the set's units. Glyphs with no paths are not returned (e.g. space etc.).
+func (fs *FontSet) StringToShape(str string) (*Shape, []*Shape, error) {
+	glyphs, _, err := fs.layoutString(str)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shape := &Shape{}
+	shapes := []*Shape{}
+	for _, g := range glyphs {
+		if len(g.shape.Paths()) > 0 {
+			// Add to result shape
+			xfm := Translate(g.x, 0)
+			s := g.shape.Transform(xfm)
+			shapes = append(shapes, s)
+			shape.AddShapes(s)
+		}
+	}
+	return shape, shapes, nil
+}
+
+// layoutString returns the glyphs of the string, including those with no paths, offset along the
+// baseline with kerning applied, and the total advance of the string, in the set's units.
+func (fs *FontSet) layoutString(str string) ([]layoutGlyph, float64, error) {
+	var buffer sfnt.Buffer
+	x := 0.0
+
+	res := []layoutGlyph{}
+	var pfont *sfnt.Font
+	pgi := sfnt.GlyphIndex(0)
+	var pr rune
+	for i, r := range str {
+		s, tfont, gi, adv, err := fs.glyph(r)
+		if err != nil {
+			return nil, 0, fmt.Errorf("glyph error at rune %d (%s)", i, err.Error())
+		}
+		if tfont == pfont {
+			// Apply any kerning
+			upem := fixed.I(int(tfont.UnitsPerEm()))
+			kern, err := tfont.Kern(&buffer, pgi, gi, upem, font.HintingNone)
+			if err != nil && err != sfnt.ErrNotFound {
+				return nil, 0, fmt.Errorf("error finding kerning for %d(%c) and %d(%c), (%s)", gi, r, pgi, pr, err.Error())
+			} else if err == nil {
+				x += I266ToF64(kern) * fs.scale(tfont)
+			}
+		}
+		pfont, pgi, pr = tfont, gi, r
+		res = append(res, layoutGlyph{r, s, x, adv})
+		x += adv
+	}
+	return res, x, nil
+}
@@ -0,0 +1,103 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"os"
+)
+
+// Demonstrates choosing fonts per rune from a font set and laying out text with it. Runes that none
+// of the fonts have use the first font's notdef glyph.
+func ExampleFontSet() {
+	mono, err := sfnt.Parse(gomono.TTF)
+	if err != nil {
+		panic(err)
+	}
+	regular, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	fs := g2d.NewFontSet(mono, regular)
+	names := map[*sfnt.Font]string{mono: "mono", regular: "regular"}
+	for _, r := range "A日" {
+		f, err := fs.FontFor(r)
+		if err != nil {
+			panic(err)
+		}
+		_, adv, _ := fs.Glyph(r)
+		fmt.Printf("%c: %s, advance %.0f\n", r, names[f], adv)
+	}
+
+	shape, glyphs, err := fs.StringToShape("Hello 日本")
+	if err != nil {
+		panic(err)
+	}
+	bb := shape.BoundingBox()
+	fmt.Printf("%d glyphs, %.0f wide\n", len(glyphs), bb[1][0]-bb[0][0])
+
+	tl := g2d.NewTextLayout(nil, 20, 0)
+	tl.Fonts = fs
+	tb, err := tl.Layout("Hello 日本")
+	if err != nil {
+		panic(err)
+	}
+	bb = tb.BoundingBox()
+	fmt.Printf("layout %.1f x %.1f\n", bb[1][0]-bb[0][0], bb[1][1]-bb[0][1])
+	// Output:
+	// A: mono, advance 1229
+	// 日: mono, advance 1229
+	// 7 glyphs, 9647 wide
+	// layout 96.0 x 23.1
+}
+
+// Demonstrates falling back to a later font in the set for runes the first font doesn't have, where
+// the fonts have different units per em. The test font only has glyphs for 0, 1, Q and 中, in 1000
+// units per em, so the glyphs taken from the Go font, in 2048 units per em, are scaled to match.
+func ExampleFontSet_fallback() {
+	data, err := os.ReadFile("testdata/CFFTest.otf")
+	if err != nil {
+		panic(err)
+	}
+	cff, err := sfnt.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	regular, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	fs := g2d.NewFontSet(cff, regular)
+	names := map[*sfnt.Font]string{cff: "cff", regular: "regular"}
+	fmt.Println("units per em", fs.UnitsPerEm())
+	for _, r := range "0A" {
+		f, err := fs.FontFor(r)
+		if err != nil {
+			panic(err)
+		}
+		shape, adv, _ := fs.Glyph(r)
+		bb := shape.BoundingBox()
+		fmt.Printf("%c: %s, advance %.1f, height %.1f\n", r, names[f], adv, bb[1][1]-bb[0][1])
+	}
+
+	shape, _, adv, _ := g2d.DefaultGlyphCache.Glyph(regular, 'A')
+	bb := shape.BoundingBox()
+	fmt.Printf("A in regular: advance %.1f, height %.1f\n", adv, bb[1][1]-bb[0][1])
+
+	// The cache holds A from both fonts, as the first font is checked first, and the scaled A
+	fs.Cache = g2d.NewGlyphCache(0)
+	for range 3 {
+		fs.Glyph('A')
+	}
+	fmt.Println("cached:", fs.Cache.Len())
+	// Output:
+	// units per em 1000
+	// 0: cff, advance 600.0, height 800.0
+	// A: regular, advance 667.0, height 722.7
+	// A in regular: advance 1366.0, height 1480.0
+	// cached: 3
+}
@@ -2,7 +2,6 @@ package graphics2d
 
 import (
 	"fmt"
-	"golang.org/x/image/font"
 	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
 )
@@ -26,37 +25,13 @@ for i:=0; i<fonts.NumFonts(); i++ {
 f := fonts.Font(0)
 */
 
-var (
-	fontCache = make(map[*sfnt.Font]map[rune]*Shape)
-	giCache   = make(map[*sfnt.Font]map[rune]sfnt.GlyphIndex)
-)
-
 // GlyphToShape returns a shape containing the paths for rune r as found in the font.
 // The path is in font units.
 // Use font.UnitsPerEm() to calculate scale factors.
+// The shape is a copy of the one held by DefaultGlyphCache.
 func GlyphToShape(font *sfnt.Font, r rune) (*Shape, error) {
-	rcache, ok := fontCache[font]
-	if !ok {
-		rcache = make(map[rune]*Shape)
-		fontCache[font] = rcache
-		giCache[font] = make(map[rune]sfnt.GlyphIndex)
-	}
-	shape, ok := rcache[r]
-	if !ok {
-		var buffer sfnt.Buffer
-		x, err := font.GlyphIndex(&buffer, r)
-		if err != nil {
-			return nil, err
-		}
-		giCache[font][r] = x
-		// x == 0 means use the unfound glyph
-		shape, err = GlyphIndexToShape(font, x)
-		if err != nil {
-			return nil, err
-		}
-		rcache[r] = shape
-	}
-	return shape, nil
+	shape, _, _, err := DefaultGlyphCache.Glyph(font, r)
+	return shape, err
 }
 
 // GlyphIndexToShape returns a shape containing the paths for glyph index x as found in the font. The path is in
@@ -101,73 +76,18 @@ func GlyphIndexToShape(font *sfnt.Font, x sfnt.GlyphIndex) (*Shape, error) {
 // StringToShape returns the string rendered as both a single shape,
 // and as individual shapes, correctly offset in font units.
 // Glyphs with no paths are not returned (e.g. space etc.).
+// Use a FontSet for strings that need more than one font.
 func StringToShape(tfont *sfnt.Font, str string) (*Shape, []*Shape, error) {
-	r2gi, ok := giCache[tfont]
-	if !ok {
-		r2gi = make(map[rune]sfnt.GlyphIndex)
-		giCache[tfont] = r2gi
-	}
-	r2s, ok := fontCache[tfont]
-	if !ok {
-		r2s = make(map[rune]*Shape)
-		fontCache[tfont] = r2s
-	}
-	r2adv := make(map[rune]float64)
-	upem := fixed.I(int(tfont.UnitsPerEm()))
-
-	var buffer sfnt.Buffer
-	x := 0.0
+	return NewFontSet(tfont).StringToShape(str)
+}
 
-	shape := &Shape{}
-	shapes := []*Shape{}
-	pgi := sfnt.GlyphIndex(0xffff)
-	var pr rune
-	for i, r := range str {
-		s, ok := r2s[r]
-		if !ok {
-			// Find the glyph index
-			gi, err := tfont.GlyphIndex(&buffer, r)
-			if err != nil {
-				return nil, nil, fmt.Errorf("gi error at rune %d (%s)", i, err.Error())
-			}
-			r2gi[r] = gi
-			// Create a shape for it
-			s, err = GlyphIndexToShape(tfont, gi)
-			if err != nil {
-				return nil, nil, err
-			}
-			r2s[r] = s
-			// Lookup its advance and convert it to float64
-			adv, err := tfont.GlyphAdvance(&buffer, gi, upem, font.HintingNone)
-			if err != nil {
-				return nil, nil, fmt.Errorf("error finding advance for index %d(%c), (%s)", gi, r, err.Error())
-			}
-			r2adv[r] = I266ToF64(adv)
-		}
-		gi := r2gi[r]
-		if pgi != 0xffff {
-			// Apply any kerning
-			kern, err := tfont.Kern(&buffer, pgi, gi, upem, font.HintingNone)
-			k := 0.0
-			if err != nil && err != sfnt.ErrNotFound {
-				return nil, nil, fmt.Errorf("error finding kerning for %d(%c) and %d(%c), (%s)", gi, r, pgi, pr, err.Error())
-			} else {
-				k = I266ToF64(kern)
-			}
-			x += k
-		}
-		pgi = gi
-		pr = r
-		if len(s.Paths()) > 0 {
-			// Add to result shape
-			xfm := Translate(x, 0)
-			s = s.Transform(xfm)
-			shapes = append(shapes, s)
-			shape.AddShapes(s)
-		}
-		x += r2adv[r]
-	}
-	return shape, shapes, nil
+// layoutGlyph is a glyph of a laid out string, its offset along the baseline and its advance, in
+// font units.
+type layoutGlyph struct {
+	r     rune
+	shape *Shape
+	x     float64
+	adv   float64
 }
 
 // I266ToF64 converts a fixed.Int26_6 to float64
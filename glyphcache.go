@@ -0,0 +1,169 @@
+package graphics2d
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphCache holds the glyph index, shape and advance, in font units, of the glyphs loaded from fonts
+// for runes, and of those glyphs scaled to the units of the font sets they're used in. It's safe for
+// concurrent use. If it has a limit, then once the limit is reached the
+// least recently used glyphs are discarded. Glyphs are loaded outside of the cache's lock so a slow
+// load doesn't hold up other users of the cache.
+type GlyphCache struct {
+	mu      sync.Mutex
+	limit   int
+	lru     *list.List // Most recently used at the front
+	entries map[glyphKey]*list.Element
+}
+
+type glyphKey struct {
+	font *sfnt.Font
+	r    rune
+	upem float64 // The units per em the glyph is scaled to
+}
+
+type glyphEntry struct {
+	key   glyphKey
+	gi    sfnt.GlyphIndex
+	shape *Shape
+	adv   float64
+}
+
+// DefaultGlyphCacheSize is the limit of the default glyph cache.
+const DefaultGlyphCacheSize = 4096
+
+// DefaultGlyphCache is used by GlyphToShape, StringToShape and the other text functions.
+var DefaultGlyphCache = NewGlyphCache(DefaultGlyphCacheSize)
+
+// NewGlyphCache creates a new glyph cache holding at most limit glyphs. If limit is 0 or less, then
+// the cache is unbounded.
+func NewGlyphCache(limit int) *GlyphCache {
+	return &GlyphCache{limit: limit, lru: list.New(), entries: make(map[glyphKey]*list.Element)}
+}
+
+// Glyph returns the shape, glyph index and advance of rune r in the font, loading them if they're
+// not already cached. The shape is in font units and is a copy of the cached one, so it's the
+// caller's to modify.
+func (gc *GlyphCache) Glyph(tfont *sfnt.Font, r rune) (*Shape, sfnt.GlyphIndex, float64, error) {
+	ge, err := gc.entry(tfont, r, float64(tfont.UnitsPerEm()))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return ge.shape.Copy(), ge.gi, ge.adv, nil
+}
+
+// entry returns the cache entry for rune r in the font, scaled to upem units per em, loading or
+// scaling it if necessary. The entry's shape is shared by all the users of the cache. Shape and Path
+// fill in their lazily computed fields, such as bounding boxes and flattened paths, when read, so the
+// shape must only be copied or transformed into a new shape and never used directly.
+func (gc *GlyphCache) entry(tfont *sfnt.Font, r rune, upem float64) (*glyphEntry, error) {
+	key := glyphKey{tfont, r, upem}
+	gc.mu.Lock()
+	if e, ok := gc.entries[key]; ok {
+		gc.lru.MoveToFront(e)
+		gc.mu.Unlock()
+		return e.Value.(*glyphEntry), nil
+	}
+	gc.mu.Unlock()
+
+	var ge *glyphEntry
+	if fupem := float64(tfont.UnitsPerEm()); upem == fupem {
+		var err error
+		ge, err = loadGlyph(tfont, r)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Scale the glyph in font units
+		fe, err := gc.entry(tfont, r, fupem)
+		if err != nil {
+			return nil, err
+		}
+		scale := upem / fupem
+		ge = &glyphEntry{key, fe.gi, fe.shape.Transform(Scale(scale, scale)), fe.adv * scale}
+	}
+
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if e, ok := gc.entries[key]; ok {
+		// Loaded concurrently, use the first
+		gc.lru.MoveToFront(e)
+		return e.Value.(*glyphEntry), nil
+	}
+	gc.entries[key] = gc.lru.PushFront(ge)
+	for gc.limit > 0 && gc.lru.Len() > gc.limit {
+		gc.remove(gc.lru.Back())
+	}
+	return ge, nil
+}
+
+// loadGlyph finds the glyph index, shape and advance for r in the font.
+func loadGlyph(tfont *sfnt.Font, r rune) (*glyphEntry, error) {
+	var buffer sfnt.Buffer
+	gi, err := tfont.GlyphIndex(&buffer, r)
+	if err != nil {
+		return nil, err
+	}
+	// gi == 0 means use the unfound glyph
+	shape, err := GlyphIndexToShape(tfont, gi)
+	if err != nil {
+		return nil, err
+	}
+	upem := fixed.I(int(tfont.UnitsPerEm()))
+	adv, err := tfont.GlyphAdvance(&buffer, gi, upem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("error finding advance for index %d(%c), (%s)", gi, r, err.Error())
+	}
+	return &glyphEntry{glyphKey{tfont, r, float64(tfont.UnitsPerEm())}, gi, shape, I266ToF64(adv)}, nil
+}
+
+// remove removes the element from the cache. The lock must be held.
+func (gc *GlyphCache) remove(e *list.Element) {
+	gc.lru.Remove(e)
+	delete(gc.entries, e.Value.(*glyphEntry).key)
+}
+
+// Evict removes all the glyphs for the font from the cache.
+func (gc *GlyphCache) Evict(tfont *sfnt.Font) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	for e := gc.lru.Front(); e != nil; {
+		next := e.Next()
+		if e.Value.(*glyphEntry).key.font == tfont {
+			gc.remove(e)
+		}
+		e = next
+	}
+}
+
+// Clear removes all the glyphs from the cache.
+func (gc *GlyphCache) Clear() {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.lru.Init()
+	gc.entries = make(map[glyphKey]*list.Element)
+}
+
+// Len returns the number of glyphs in the cache.
+func (gc *GlyphCache) Len() int {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.lru.Len()
+}
+
+// SetLimit changes the limit of the cache, discarding the least recently used glyphs if necessary.
+// If limit is 0 or less, then the cache is unbounded.
+func (gc *GlyphCache) SetLimit(limit int) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.limit = limit
+	for gc.limit > 0 && gc.lru.Len() > gc.limit {
+		gc.remove(gc.lru.Back())
+	}
+}
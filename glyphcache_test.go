@@ -0,0 +1,84 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"sync"
+)
+
+// Demonstrates a bounded glyph cache shared by several goroutines, and evicting a font from it.
+func ExampleGlyphCache() {
+	regular, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	bold, err := sfnt.Parse(gobold.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	cache := g2d.NewGlyphCache(5)
+	var wg sync.WaitGroup
+	for _, r := range "abcdefgh" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, _, err := cache.Glyph(regular, r); err != nil {
+				panic(err)
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println("cached:", cache.Len())
+
+	for _, r := range "xy" {
+		_, _, adv, _ := cache.Glyph(bold, r)
+		fmt.Printf("advance of %c: %.0f\n", r, adv)
+	}
+	cache.Evict(regular)
+	fmt.Println("after evicting regular:", cache.Len())
+	// Output:
+	// cached: 5
+	// advance of x: 1139
+	// advance of y: 1139
+	// after evicting regular: 2
+}
+
+// Demonstrates using the glyphs of a font from several goroutines at once, via StringToShape,
+// warped TextOnPath and GlyphToShape, with the same glyphs shared through DefaultGlyphCache. Run
+// with -race to check that none of them modify the cached glyphs.
+func ExampleGlyphCache_concurrent() {
+	ttf, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	bbs := make([][][]float64, 8)
+	var wg sync.WaitGroup
+	for i := range bbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := g2d.StringToShape(ttf, "banana"); err != nil {
+				panic(err)
+			}
+			arc := g2d.NewPath([]float64{0, 0})
+			arc.AddStep([]float64{200, 200}, []float64{400, 0})
+			opts := &g2d.TextOnPathOptions{Size: 40, Warp: true}
+			if _, _, err := g2d.TextOnPath(ttf, "banana", arc, opts); err != nil {
+				panic(err)
+			}
+			shape, err := g2d.GlyphToShape(ttf, 'a')
+			if err != nil {
+				panic(err)
+			}
+			bbs[i] = shape.BoundingBox()
+		}()
+	}
+	wg.Wait()
+	fmt.Println(bbs[0], bbs[len(bbs)-1])
+	// Output: [[95 -1110] [1098 25]] [[95 -1110] [1098 25]]
+}
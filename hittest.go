@@ -0,0 +1,85 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Distance and hit testing queries. The closest point on a part is found from the roots of the
+// derivative of the squared distance to it, rather than by searching on t, so it isn't confused by
+// parts that loop back on themselves. Hit testing strokes the path with the pen and counts the
+// crossings of the resultant outline's steps directly, so no rasterization or flattening is involved.
+
+// ClosestPoint returns the point on the path closest to pt, its t on the path and the distance to it.
+// Unlike ProjectPoint, t is for the path itself and not its simplified form.
+func (p *Path) ClosestPoint(pt []float64) ([]float64, float64, float64) {
+	parts := p.Parts()
+	n := len(parts)
+	bi, bt, bd := 0, 0.0, math.MaxFloat64
+	for i, part := range parts {
+		t, d := util.ClosestT(part, pt)
+		if d < bd {
+			bi, bt, bd = i, t, d
+		}
+	}
+	cp := util.DeCasteljau(parts[bi], bt)
+	return []float64{cp[0], cp[1]}, (float64(bi) + bt) / float64(n), math.Sqrt(bd)
+}
+
+// DistanceTo returns the distance from pt to the closest point on the path.
+func (p *Path) DistanceTo(pt []float64) float64 {
+	_, _, d := p.ClosestPoint(pt)
+	return d
+}
+
+// HitTest returns true if pt lies within the area that would be rendered by drawing the path with
+// the pen, i.e. within the stroke width, joins and caps of the pen's stroke processor. If the pen has
+// no stroke, or is nil, then the path is treated as filled.
+func (p *Path) HitTest(pt []float64, pen *Pen) bool {
+	return NewShape(p).HitTest(pt, pen)
+}
+
+// HitTest returns true if pt lies within the area that would be rendered by drawing the shape with
+// the pen. See Path.HitTest.
+func (s *Shape) HitTest(pt []float64, pen *Pen) bool {
+	if pen == nil {
+		return s.rule.Inside(s.windingNumber(pt))
+	}
+	if pen.Xfm != nil {
+		s = s.Transform(pen.Xfm)
+	}
+	if pen.Stroke != nil {
+		// As when drawing, the stroke outline is filled NonZero
+		s = s.ProcessPaths(pen.Stroke).SetFillRule(NonZero)
+	}
+	return s.rule.Inside(s.windingNumber(pt))
+}
+
+// windingNumber returns the winding number of pt with respect to the shape's paths, which are forced
+// closed, calculated from the steps rather than their flattened form.
+func (s *Shape) windingNumber(pt []float64) int {
+	edges := []*bedge{}
+	for _, path := range s.paths {
+		bb := path.BoundingBox()
+		if pt[1] < bb[0][1] || pt[1] > bb[1][1] || pt[0] > bb[1][0] {
+			continue
+		}
+		parts := path.Parts()
+		if len(parts) == 0 || len(parts[0]) == 1 {
+			continue
+		}
+		if !path.closed {
+			// Force closed
+			start, end := parts[0][0], parts[len(parts)-1]
+			last := end[len(end)-1]
+			if !util.EqualsP(start, last) {
+				parts = append(parts, Part{last, start})
+			}
+		}
+		for _, part := range parts {
+			edges = append(edges, &bedge{part: part, bb: util.BoundingBox(part...)})
+		}
+	}
+	return windingNumber(pt, edges)
+}
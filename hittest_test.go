@@ -0,0 +1,75 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+)
+
+// Demonstrates distance queries and hit testing a path against pens with different caps.
+func ExamplePath_HitTest() {
+	path := g2d.PolyLine([]float64{0, 0}, []float64{100, 0}, []float64{100, 100})
+	fmt.Printf("distance %.2f\n", path.DistanceTo([]float64{50, 10}))
+
+	curve := g2d.NewPath([]float64{0, 0})
+	curve.AddStep([]float64{50, 100}, []float64{100, 0})
+	cpt, t, d := curve.ClosestPoint([]float64{50, 60})
+	fmt.Printf("closest %.2f,%.2f t %.2f distance %.2f\n", cpt[0], cpt[1], t, d)
+
+	butt := g2d.NewStrokedPen(color.Black, 20, nil, g2d.CapButt)
+	round := g2d.NewStrokedPen(color.Black, 20, nil, g2d.CapRound)
+	for _, pt := range [][]float64{{50, 4}, {50, 6}, {103, -3}, {-3, 0}, {100, 103}} {
+		fmt.Printf("%v butt %v round %v\n", pt, path.HitTest(pt, butt), path.HitTest(pt, round))
+	}
+	// Output:
+	// distance 10.00
+	// closest 50.00,50.00 t 0.50 distance 10.00
+	// [50 4] butt true round true
+	// [50 6] butt false round false
+	// [103 -3] butt false round false
+	// [-3 0] butt false round true
+	// [100 103] butt false round true
+}
+
+// Demonstrates that hit testing a self-overlapping stroke finds the overlap whatever the fill rule of
+// the shape being stroked.
+func ExampleShape_HitTest() {
+	hairpin := g2d.PolyLine([]float64{20, 50}, []float64{180, 50}, []float64{20, 60})
+	pen := g2d.NewStrokedPen(color.Black, 30, g2d.JoinRound, nil)
+	for _, rule := range []g2d.FillRule{g2d.NonZero, g2d.EvenOdd} {
+		shape := g2d.NewShape(hairpin).SetFillRule(rule)
+		fmt.Printf("%s %v, ", rule, shape.HitTest([]float64{100, 55}, pen))
+	}
+	// Output: nonzero true, evenodd true,
+}
+
+// Demonstrates hit testing points level with the vertices of a filled shape. As with lines, a curve's
+// end point is only counted where it's the lower end of the curve.
+func ExampleShape_HitTest_vertex() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{5, 10})
+	path.AddStep([]float64{8, 6}, []float64{10, 0})
+	path.Close()
+	shape := g2d.NewShape(path)
+	pen := &g2d.Pen{Filler: g2d.NewPen(color.Black, 1).Filler}
+	for _, pt := range [][]float64{{1, 10}, {4.9, 10}, {-1, 0}, {5, 0}, {5, 5}} {
+		fmt.Printf("%v %v %v\n", pt, shape.HitTest(pt, pen), shape.PointInShape(pt))
+	}
+	// Output:
+	// [1 10] false false
+	// [4.9 10] false false
+	// [-1 0] false false
+	// [5 0] true true
+	// [5 5] true true
+}
+
+// Demonstrates hit testing with a nil pen, which treats the shape as filled.
+func ExampleShape_HitTest_nilPen() {
+	square := g2d.Polygon([]float64{0, 0}, []float64{10, 0}, []float64{10, 10}, []float64{0, 10})
+	shape := g2d.NewShape(square)
+	pen := g2d.NewStrokedPen(color.Black, 4, nil, nil)
+	fmt.Println(shape.HitTest([]float64{5, 5}, nil), shape.HitTest([]float64{5, 5}, pen),
+		shape.HitTest([]float64{1, 5}, pen))
+	// Output:
+	// true false true
+}
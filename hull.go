@@ -0,0 +1,83 @@
+package graphics2d
+
+import "github.com/jphsd/graphics2d/util"
+
+// hullFlatness is the maximum distance, relative to the size of the curve, that the control points of
+// a piece of the curve may lie from the piece's chord before the piece is subdivided further.
+const hullFlatness = 1e-3
+
+// hullPoints returns points on, or outside of, the shape's paths suitable for finding its convex hull.
+// These are the step end points and the control points of the curves, cut back to the curve extremities
+// and subdivided until they lie close to the curve. Since a curve lies within the hull of its control
+// points, the hull of these points always contains the shape and is independent of its scale.
+func (s *Shape) hullPoints() [][]float64 {
+	res := [][]float64{}
+	for _, path := range s.paths {
+		for _, part := range path.Parts() {
+			if len(part) < 3 {
+				res = append(res, part[0], part[len(part)-1])
+				continue
+			}
+			bb := util.BoundingBox(part...)
+			d2 := hullFlatness * hullFlatness * util.DistanceESquared(bb[0], bb[1])
+			ts := util.CalcExtremities(part)
+			rest, t0 := part, 0.0
+			for _, t := range ts[1:] {
+				var piece [][]float64
+				if t < 1 {
+					lr := util.SplitCurve(rest, (t-t0)/(1-t0))
+					piece, rest = lr[0], lr[1]
+				} else {
+					piece = rest
+				}
+				res = hullPiecePoints(piece, d2, 0, res)
+				t0 = t
+			}
+		}
+	}
+	return res
+}
+
+// hullPiecePoints appends the control points of the curve piece to res, subdividing the piece
+// until the squared distance of its control points from its chord is within d2.
+func hullPiecePoints(piece [][]float64, d2 float64, depth int, res [][]float64) [][]float64 {
+	n := len(piece)
+	flat := true
+	for _, cp := range piece[1 : n-1] {
+		if cd2, _, _ := util.DistanceToLineSquared(piece[0], piece[n-1], cp); cd2 > d2 {
+			flat = false
+			break
+		}
+	}
+	if flat || depth > 8 {
+		for _, cp := range piece {
+			res = append(res, []float64{cp[0], cp[1]})
+		}
+		return res
+	}
+	lr := util.SplitCurve(piece, 0.5)
+	res = hullPiecePoints(lr[0], d2, depth+1, res)
+	return hullPiecePoints(lr[1], d2, depth+1, res)
+}
+
+// ConvexHull returns the convex hull of the shape as a closed, counter-clockwise path.
+func (s *Shape) ConvexHull() *Path {
+	hull := util.ConvexHull(s.hullPoints()...)
+	if len(hull) == 0 {
+		return nil
+	}
+	return Polygon(hull...)
+}
+
+// MinAreaRect returns the minimum area rectangle enclosing the shape, as a closed, counter-clockwise
+// path, and its rotation angle in [-Pi/4, Pi/4). The first step of the path is the corner that's the
+// minimum x and y of the rectangle when rotated by -angle and the second step lies along the angle,
+// so the first two points of the rectangle can be used with LineTransform or BoxTransform to align it.
+func (s *Shape) MinAreaRect() (*Path, float64) {
+	hull := util.ConvexHull(s.hullPoints()...)
+	if len(hull) == 0 {
+		return nil, 0
+	}
+	corners, th := util.MinAreaRect(hull...)
+	return Polygon(corners...), th
+}
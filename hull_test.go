@@ -0,0 +1,82 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/util"
+	"math"
+)
+
+// Demonstrates finding the convex hull and minimum area rectangle of a rotated shape, and using the
+// rectangle to align the shape with the axes.
+func ExampleShape_MinAreaRect() {
+	shape := g2d.NewShape(g2d.Circle([]float64{0, 0}, 50), g2d.Rectangle([]float64{100, 0}, 200, 60))
+	shape = shape.Transform(g2d.RotateAbout(math.Pi/6, 0, 0))
+
+	hull := shape.ConvexHull()
+	fmt.Printf("hull %d steps, area %.0f\n", len(hull.Steps()), hull.Area())
+
+	rect, th := shape.MinAreaRect()
+	pts := rect.Steps()
+	fmt.Printf("rect area %.0f angle %.2f degrees\n", rect.Area(), th*180/math.Pi)
+
+	// Map the rectangle's first edge onto the x axis
+	p0, p1 := pts[0][0], pts[1][0]
+	w := math.Hypot(p1[0]-p0[0], p1[1]-p0[1])
+	xfm := g2d.LineTransform(p0[0], p0[1], p1[0], p1[1], 0, 0, w, 0)
+	bb := shape.Transform(xfm).TightBoundingBox()
+	fmt.Printf("aligned [%.2f %.2f] [%.2f %.2f]\n", bb[0][0], bb[0][1], bb[1][0], bb[1][1])
+	// Output:
+	// hull 145 steps, area 19979
+	// rect area 25000 angle 30.00 degrees
+	// aligned [0.00 -0.00] [250.00 100.00]
+}
+
+// Demonstrates the hull and minimum area rectangle of a shape whose points all coincide.
+func ExampleShape_MinAreaRect_point() {
+	p := []float64{10, 20}
+	shape := g2d.NewShape(g2d.Line(p, p))
+	fmt.Println("hull", shape.ConvexHull().Steps())
+	rect, th := shape.MinAreaRect()
+	fmt.Println("rect", rect.Steps(), th)
+	corners, th := util.MinAreaRect([]float64{1, 1}, []float64{1, 1}, []float64{1, 1})
+	fmt.Println("util", corners, th)
+	// Output:
+	// hull [[[10 20]]]
+	// rect [[[10 20]]] 0
+	// util [[1 1] [1 1] [1 1] [1 1]] 0
+}
+
+// Demonstrates that the hull of a small circle contains the circle and that the minimum area
+// rectangle is the square enclosing it.
+func ExampleShape_ConvexHull() {
+	shape := g2d.NewShape(g2d.Circle([]float64{0, 0}, 1))
+	hull := shape.ConvexHull()
+	fmt.Printf("hull area %.4f\n", hull.Area())
+
+	// Every point sampled on the circle lies inside, or on, every edge of the counter-clockwise hull
+	hpts := [][]float64{}
+	for _, step := range hull.Steps() {
+		hpts = append(hpts, step[0])
+	}
+	contained := true
+	for _, part := range shape.Paths()[0].Parts() {
+		for i := 0; i <= 100; i++ {
+			pt := util.DeCasteljau(part, float64(i)/100)
+			for j, p0 := range hpts {
+				p1 := hpts[(j+1)%len(hpts)]
+				if util.CrossProduct(p0, p1, pt) > 1e-10 {
+					contained = false
+				}
+			}
+		}
+	}
+	fmt.Println("contains circle", contained)
+
+	rect, th := shape.MinAreaRect()
+	fmt.Printf("rect area %.4f angle %.2f degrees\n", rect.Area(), th*180/math.Pi)
+	// Output:
+	// hull area 3.1420
+	// contains circle true
+	// rect area 4.0000 angle -45.00 degrees
+}
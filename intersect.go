@@ -0,0 +1,508 @@
+package graphics2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Intersection finding between parts of any order. Curves are recursively subdivided while their
+// control point bounding boxes overlap, until the pieces are flat enough to be treated as lines.
+// The line intersection is then projected back onto the original curves to recover exact t values.
+// Curves that lie on each other never separate under subdivision, so they're checked for first.
+
+// Intersection describes a point where two paths, or a path and itself, meet. The parts are indices
+// into the slices returned by Parts() (so the step index is one greater) and the t values are local
+// to those parts.
+type Intersection struct {
+	Point   []float64
+	Part1   int
+	T1      float64
+	Part2   int
+	T2      float64
+	Tangent bool // The paths are tangential at the point, i.e. they may touch rather than cross
+}
+
+// Intersections returns all the points where the path meets the other path, ordered by their
+// location along this path. Where parts of the paths overlap, the ends of the overlap are returned.
+func (p *Path) Intersections(other *Path) []Intersection {
+	parts1, parts2 := p.Parts(), other.Parts()
+	bbs2 := make([][][]float64, len(parts2))
+	for j, part := range parts2 {
+		bbs2[j] = util.BoundingBox(part...)
+	}
+	res := []Intersection{}
+	for i, part1 := range parts1 {
+		bb1 := util.BoundingBox(part1...)
+		for j, part2 := range parts2 {
+			if !bbNear(bb1, bbs2[j], touchDistance) {
+				continue
+			}
+			for _, ts := range partIntersections(part1, part2) {
+				res = addIntersection(res, part1, i, ts[0], part2, j, ts[1])
+			}
+		}
+	}
+	return res
+}
+
+// SelfIntersections returns all the points where the path crosses or touches itself, other than
+// where adjacent parts meet. Part1 and T1 always precede Part2 and T2 along the path, and the result
+// is ordered by Part1 and T1.
+func (p *Path) SelfIntersections() []Intersection {
+	parts := p.Parts()
+	n := len(parts)
+	res := []Intersection{}
+	if n == 0 || len(parts[0]) == 1 {
+		return res
+	}
+	closed := util.EqualsP(parts[0][0], parts[n-1][len(parts[n-1])-1])
+
+	// Break parts into pieces at their extremities so that no piece can intersect itself
+	type piece struct {
+		part   Part
+		pi     int     // part index
+		t0, t1 float64 // range in part
+		bb     [][]float64
+	}
+	pieces := []piece{}
+	for i, part := range parts {
+		ts := []float64{0, 1}
+		if len(part) > 3 {
+			ts = util.CalcExtremities(part)
+		}
+		for k := 1; k < len(ts); k++ {
+			if ts[k]-ts[k-1] < 1e-9 {
+				continue
+			}
+			sp := subPart(part, ts[k-1], ts[k])
+			pieces = append(pieces, piece{sp, i, ts[k-1], ts[k], util.BoundingBox(sp...)})
+		}
+	}
+
+	const te = 1e-9
+	np := len(pieces)
+	for a := range np {
+		pa := pieces[a]
+		for b := a + 1; b < np; b++ {
+			pb := pieces[b]
+			if !bbNear(pa.bb, pb.bb, touchDistance) {
+				continue
+			}
+			for _, ts := range partIntersections(pa.part, pb.part) {
+				ta := pa.t0 + ts[0]*(pa.t1-pa.t0)
+				tb := pb.t0 + ts[1]*(pb.t1-pb.t0)
+				// Ignore where consecutive pieces meet
+				if b == a+1 && ts[0] > 1-te && ts[1] < te {
+					continue
+				}
+				if closed && a == 0 && b == np-1 && ts[0] < te && ts[1] > 1-te {
+					continue
+				}
+				res = addIntersection(res, parts[pa.pi], pa.pi, ta, parts[pb.pi], pb.pi, tb)
+			}
+		}
+	}
+	return res
+}
+
+// addIntersection inserts the intersection into res, which is ordered by part and t of the first
+// path, unless it's a duplicate.
+func addIntersection(res []Intersection, part1 Part, i int, t1 float64, part2 Part, j int, t2 float64) []Intersection {
+	pt1, pt2 := util.DeCasteljau(part1, t1), util.DeCasteljau(part2, t2)
+	pt := []float64{(pt1[0] + pt2[0]) / 2, (pt1[1] + pt2[1]) / 2}
+	for _, is := range res {
+		if util.DistanceESquared(is.Point, pt) < util.Epsilon*util.Epsilon {
+			return res
+		}
+	}
+	dx1, dy1 := partTangent(part1, t1)
+	dx2, dy2 := partTangent(part2, t2)
+	tang := math.Abs(dx1*dy2-dy1*dx2) < 1e-6
+	is := Intersection{pt, i, t1, j, t2, tang}
+	k := len(res)
+	for k > 0 && (res[k-1].Part1 > i || res[k-1].Part1 == i && res[k-1].T1 > t1) {
+		k--
+	}
+	res = append(res, Intersection{})
+	copy(res[k+1:], res[k:])
+	res[k] = is
+	return res
+}
+
+// IntersectFlatten is the flatness below which a curve piece is treated as a line during
+// intersection finding.
+var IntersectFlatten = 1e-7
+
+// maxIntersectDepth bounds the subdivision of (near) coincident curves.
+const maxIntersectDepth = 48
+
+// partIntersections returns the pairs of t values, {t1, t2}, where part1 and part2 meet.
+// Where the parts overlap, the t values of the ends of the overlap are returned. Points where the
+// parts touch without crossing are included.
+func partIntersections(part1, part2 Part) [][]float64 {
+	if !bbNear(util.BoundingBox(part1...), util.BoundingBox(part2...), touchDistance) {
+		return nil
+	}
+	// Coincident curves never separate under subdivision, so overlaps are found directly
+	if tps := partOverlap(part1, part2); tps != nil {
+		return tps
+	}
+	res := curveIntersections(part1, part2, 0, 1, 0, 1, 0, nil)
+
+	// Touches are found as runs of nearby candidates, replace each run with the point of closest
+	// approach. Crossings are kept in preference to touches.
+	crossings, touches := [][]float64{}, [][]float64{}
+	for _, tp := range res {
+		if len(tp) == 2 {
+			crossings = append(crossings, tp)
+		} else {
+			touches = append(touches, tp)
+		}
+	}
+	if len(touches) == 0 {
+		return dedupTPairs(part1, part2, crossings)
+	}
+	sort.Slice(touches, func(i, j int) bool { return touches[i][0] < touches[j][0] })
+	dist := func(t float64) (float64, float64) {
+		t2, d2 := util.ClosestT(part2, util.DeCasteljau(part1, t))
+		return d2, t2
+	}
+	td2 := touchDistance * touchDistance
+	// A touch in the same contact region as a crossing is already accounted for
+	nearCrossing := func(t float64) bool {
+		for _, tp := range crossings {
+			if d2, _ := dist((t + tp[0]) / 2); d2 < td2 {
+				return true
+			}
+		}
+		return false
+	}
+	res = crossings
+	lo := touches[0][0]
+	for i, tp := range touches {
+		hi := tp[0]
+		if i < len(touches)-1 {
+			// Continue the run if the parts are still touching between the candidates
+			if d2, _ := dist((hi + touches[i+1][0]) / 2); d2 < td2 {
+				continue
+			}
+		}
+		// Widen the run while the parts continue to approach each other
+		f := func(t float64) float64 { d2, _ := dist(t); return d2 }
+		for w := 1e-6; lo > 0 && f(math.Max(lo-w, 0)) < f(lo); w *= 2 {
+			lo = math.Max(lo-w, 0)
+		}
+		for w := 1e-6; hi < 1 && f(math.Min(hi+w, 1)) < f(hi); w *= 2 {
+			hi = math.Min(hi+w, 1)
+		}
+		t1 := goldenMin(f, lo, hi)
+		if d2, t2 := dist(t1); d2 < td2 && !nearCrossing(t1) {
+			res = append(res, []float64{t1, t2})
+		}
+		if i < len(touches)-1 {
+			lo = touches[i+1][0]
+		}
+	}
+	return dedupTPairs(part1, part2, res)
+}
+
+// overlapTolerance is the distance within which the control points of two curve pieces must agree
+// for them to be treated as the same curve.
+const overlapTolerance = 1e-6
+
+// partOverlap returns the pairs of t values, {t1, t2}, at the ends of the stretch where the curves
+// part1 and part2 lie on each other, or nil if they don't. The ends of an overlap are end points of
+// one or other of the parts, so the parts overlap if the pieces of them between those points have
+// the same control points.
+func partOverlap(part1, part2 Part) [][]float64 {
+	n := len(part1)
+	if n < 3 || n != len(part2) {
+		// Lines are handled by lineIntersections
+		return nil
+	}
+	td2 := overlapTolerance * overlapTolerance
+	ends := [][]float64{}
+	for i, pt := range [][]float64{part2[0], part2[n-1]} {
+		if t, d2 := util.ClosestT(part1, pt); d2 < td2 {
+			ends = append(ends, []float64{t, float64(i)})
+		}
+	}
+	for i, pt := range [][]float64{part1[0], part1[n-1]} {
+		if t, d2 := util.ClosestT(part2, pt); d2 < td2 {
+			ends = append(ends, []float64{float64(i), t})
+		}
+	}
+	if len(ends) < 2 {
+		return nil
+	}
+	sort.Slice(ends, func(i, j int) bool { return ends[i][0] < ends[j][0] })
+	lo, hi := ends[0], ends[len(ends)-1]
+	if hi[0]-lo[0] < 1e-9 || math.Abs(hi[1]-lo[1]) < 1e-9 {
+		// The parts only meet at a point
+		return nil
+	}
+	sp1 := subPart(part1, lo[0], hi[0])
+	var sp2 Part
+	if lo[1] < hi[1] {
+		sp2 = subPart(part2, lo[1], hi[1])
+	} else {
+		sp2 = ReversePoints(subPart(part2, hi[1], lo[1]))
+	}
+	for i := range n {
+		if util.DistanceESquared(sp1[i], sp2[i]) > td2 {
+			return nil
+		}
+	}
+	return [][]float64{lo, hi}
+}
+
+// goldenMin returns the location of the minimum of f in [a, b] using golden section search.
+func goldenMin(f func(float64) float64, a, b float64) float64 {
+	const ig = 0.6180339887498949
+	c, d := b-ig*(b-a), a+ig*(b-a)
+	fc, fd := f(c), f(d)
+	for b-a > 1e-12 {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - ig*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ig*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
+}
+
+// touchDistance is the separation below which flat pieces are considered to touch.
+const touchDistance = 4e-7
+
+func curveIntersections(p1, p2 Part, s1, e1, s2, e2 float64, depth int, res [][]float64) [][]float64 {
+	if !bbNear(util.BoundingBox(p1...), util.BoundingBox(p2...), touchDistance) {
+		return res
+	}
+	f1, f2 := flatWithin(IntersectFlatten, p1), flatWithin(IntersectFlatten, p2)
+	if (f1 && f2) || depth > maxIntersectDepth {
+		l1, l2 := len(p1)-1, len(p2)-1
+		tps := lineIntersections(p1[0], p1[l1], p2[0], p2[l2])
+		if len(tps) == 0 {
+			// Check for a touch
+			ta, tb, d2 := segmentsClosest(p1[0], p1[l1], p2[0], p2[l2])
+			if d2 < touchDistance*touchDistance {
+				tps = [][]float64{{ta, tb, 1}}
+			}
+		}
+		for _, ts := range tps {
+			// Project the chord intersection back onto the pieces
+			pt := Lerp(ts[0], p1[0], p1[l1])
+			t1, t2 := ts[0], ts[1]
+			if l1 > 1 {
+				t1, _ = util.ClosestT(p1, pt)
+			}
+			if l2 > 1 {
+				t2, _ = util.ClosestT(p2, pt)
+			}
+			if len(ts) > 2 {
+				res = append(res, []float64{s1 + t1*(e1-s1), s2 + t2*(e2-s2), 1})
+			} else {
+				res = append(res, []float64{s1 + t1*(e1-s1), s2 + t2*(e2-s2)})
+			}
+		}
+		return res
+	}
+
+	// A flat piece's chord separating the other piece's control points rules out an intersection
+	if (f2 && chordSeparates(p2, p1)) || (f1 && chordSeparates(p1, p2)) {
+		return res
+	}
+
+	// Subdivide the larger of the non-flat pieces
+	split1 := !f1
+	if !f1 && !f2 {
+		split1 = bbDiag(p1) > bbDiag(p2)
+	}
+	if split1 {
+		l, r := splitPart(p1, 0.5)
+		m := (s1 + e1) / 2
+		res = curveIntersections(l, p2, s1, m, s2, e2, depth+1, res)
+		return curveIntersections(r, p2, m, e1, s2, e2, depth+1, res)
+	}
+	l, r := splitPart(p2, 0.5)
+	m := (s2 + e2) / 2
+	res = curveIntersections(p1, l, s1, e1, s2, m, depth+1, res)
+	return curveIntersections(p1, r, s1, e1, m, e2, depth+1, res)
+}
+
+// flatWithin returns true if the control points of the part are within d of the line joining
+// its end points. Unlike cpWithinD2, it remains accurate for very small parts.
+func flatWithin(d float64, part Part) bool {
+	n := len(part) - 1
+	if n == 1 {
+		return true
+	}
+	start, end := part[0], part[n]
+	l := util.DistanceE(start, end)
+	for _, cp := range part[1:n] {
+		if l == 0 {
+			if util.DistanceE(start, cp) > d {
+				return false
+			}
+		} else if math.Abs(util.CrossProduct(start, end, cp)) > d*l {
+			return false
+		}
+	}
+	return true
+}
+
+// chordSeparates returns true if all of the control points of part lie more than touchDistance to
+// one side of the line through the end points of flat.
+func chordSeparates(flat, part Part) bool {
+	start, end := flat[0], flat[len(flat)-1]
+	l := util.DistanceE(start, end)
+	if l == 0 {
+		return false
+	}
+	d := touchDistance * l
+	above, below := false, false
+	for _, cp := range part {
+		cr := util.CrossProduct(start, end, cp)
+		if cr > -d {
+			above = true
+		}
+		if cr < d {
+			below = true
+		}
+	}
+	return !above || !below
+}
+
+// bbNear returns true if the bounding boxes are within d of each other.
+func bbNear(bb1, bb2 [][]float64, d float64) bool {
+	return bb1[0][0] <= bb2[1][0]+d && bb2[0][0] <= bb1[1][0]+d &&
+		bb1[0][1] <= bb2[1][1]+d && bb2[0][1] <= bb1[1][1]+d
+}
+
+// segmentsClosest returns the t values of the closest points on two non-intersecting line segments
+// and the distance squared between them.
+func segmentsClosest(a0, a1, b0, b1 []float64) (float64, float64, float64) {
+	ta, tb, bd := 0.0, 0.0, math.MaxFloat64
+	for i, ap := range [][]float64{a0, a1} {
+		t, d2 := util.ClosestT([][]float64{b0, b1}, ap)
+		if d2 < bd {
+			ta, tb, bd = float64(i), t, d2
+		}
+	}
+	for i, bp := range [][]float64{b0, b1} {
+		t, d2 := util.ClosestT([][]float64{a0, a1}, bp)
+		if d2 < bd {
+			ta, tb, bd = t, float64(i), d2
+		}
+	}
+	return ta, tb, bd
+}
+
+func bbDiag(part Part) float64 {
+	bb := util.BoundingBox(part...)
+	return util.DistanceE(bb[0], bb[1])
+}
+
+// lineIntersections returns the t values for the two line segments where they intersect.
+// If the lines are collinear and overlap, then the t values for the overlap ends are returned.
+func lineIntersections(a0, a1, b0, b1 []float64) [][]float64 {
+	const e = 1e-9
+	ax, ay := a1[0]-a0[0], a1[1]-a0[1]
+	bx, by := b1[0]-b0[0], b1[1]-b0[1]
+	la, lb := math.Hypot(ax, ay), math.Hypot(bx, by)
+	if la == 0 || lb == 0 {
+		return nil
+	}
+	d := by*ax - bx*ay
+	if math.Abs(d) > e*la*lb {
+		cx, cy := a0[0]-b0[0], a0[1]-b0[1]
+		ta, tb := (bx*cy-by*cx)/d, (ax*cy-ay*cx)/d
+		if ta < -e || ta > 1+e || tb < -e || tb > 1+e {
+			return nil
+		}
+		return [][]float64{{clamp01(ta), clamp01(tb)}}
+	}
+
+	// Parallel - check for collinearity
+	if math.Abs(util.CrossProduct(a0, a1, b0)) > IntersectFlatten*la {
+		return nil
+	}
+	la2, lb2 := la*la, lb*lb
+	proj := func(p, o []float64, dx, dy, l2 float64) float64 {
+		return ((p[0]-o[0])*dx + (p[1]-o[1])*dy) / l2
+	}
+	res := [][]float64{}
+	for i, bp := range [][]float64{b0, b1} {
+		t := proj(bp, a0, ax, ay, la2)
+		if t > -e && t < 1+e {
+			res = append(res, []float64{clamp01(t), float64(i)})
+		}
+	}
+	for i, ap := range [][]float64{a0, a1} {
+		t := proj(ap, b0, bx, by, lb2)
+		if t > -e && t < 1+e {
+			res = append(res, []float64{float64(i), clamp01(t)})
+		}
+	}
+	return res
+}
+
+func clamp01(t float64) float64 {
+	if t < 0 {
+		return 0
+	}
+	if t > 1 {
+		return 1
+	}
+	return t
+}
+
+// dedupTPairs removes pairs that describe the same point, as found at subdivision boundaries.
+func dedupTPairs(part1, part2 Part, tps [][]float64) [][]float64 {
+	res := [][]float64{}
+	pts := [][]float64{}
+	for _, tp := range tps {
+		pt := util.DeCasteljau(part1, tp[0])
+		dup := false
+		for i, rtp := range res {
+			if math.Abs(rtp[0]-tp[0]) < 1e-7 && math.Abs(rtp[1]-tp[1]) < 1e-7 ||
+				util.DistanceESquared(pts[i], pt) < util.Epsilon*util.Epsilon {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			res = append(res, tp)
+			pts = append(pts, pt)
+		}
+	}
+	return res
+}
+
+// splitPart splits the part at t. Unlike util.SplitCurve, the points in the results only contain
+// x and y.
+func splitPart(part Part, t float64) (Part, Part) {
+	lr := util.SplitCurve(part, t)
+	return toPart(lr[0][0], lr[0][1:]), toPart(lr[1][0], lr[1][1:])
+}
+
+// subPart returns the piece of the part between t0 and t1, t0 < t1.
+func subPart(part Part, t0, t1 float64) Part {
+	if t0 <= 0 && t1 >= 1 {
+		return toPart(part[0], part[1:])
+	}
+	res := part
+	if t1 < 1 {
+		res, _ = splitPart(res, t1)
+	}
+	if t0 > 0 {
+		_, res = splitPart(res, t0/t1)
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates finding where two paths cross or touch, and where a path crosses itself.
+func ExamplePath_Intersections() {
+	c1 := g2d.Circle([]float64{0, 0}, 10)
+	c2 := g2d.Circle([]float64{10, 0}, 10)
+	c3 := g2d.Circle([]float64{20, 0}, 10)
+
+	for _, is := range c1.Intersections(c2) {
+		fmt.Printf("crossing %.2f,%.2f tangent %v\n", is.Point[0], is.Point[1], is.Tangent)
+	}
+	for _, is := range c1.Intersections(c3) {
+		fmt.Printf("touch %.2f,%.2f tangent %v\n", is.Point[0], is.Point[1], is.Tangent)
+	}
+
+	loop := g2d.NewPath([]float64{0, 0})
+	loop.AddStep([]float64{20, 10}, []float64{-10, 10}, []float64{10, 0})
+	for _, is := range loop.SelfIntersections() {
+		fmt.Printf("loop %.2f,%.2f at t %.4f and %.4f\n", is.Point[0], is.Point[1], is.T1, is.T2)
+	}
+	// Output:
+	// crossing 5.00,8.66 tangent false
+	// crossing 5.00,-8.66 tangent false
+	// touch 10.00,0.00 tangent true
+	// loop 5.00,3.00 at t 0.1127 and 0.8873
+}
+
+// Demonstrates that where paths overlap, rather than cross, the ends of the overlap are returned.
+func ExamplePath_Intersections_overlap() {
+	arc := g2d.NewPath([]float64{0, 0})
+	arc.AddStep([]float64{0, 50}, []float64{50, 100}, []float64{100, 100})
+
+	for _, is := range arc.Intersections(arc.SubPath(0.25, 0.75)) {
+		fmt.Printf("sub path %.2f,%.2f at t %.2f and %.2f\n", is.Point[0], is.Point[1], is.T1, is.T2)
+	}
+	for _, is := range arc.Intersections(arc.Reverse()) {
+		fmt.Printf("reversed %.2f,%.2f at t %.2f and %.2f\n", is.Point[0], is.Point[1], is.T1, is.T2)
+	}
+	// Output:
+	// sub path 8.59,36.72 at t 0.25 and 0.00
+	// sub path 63.28,91.41 at t 0.75 and 1.00
+	// reversed 0.00,0.00 at t 0.00 and 1.00
+	// reversed 100.00,100.00 at t 1.00 and 0.00
+}
@@ -0,0 +1,218 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Path markers, such as arrowheads, dots and bars, placed at the start, end and vertices of a path
+// as in SVG. Marker shapes are defined in marker units, where a unit is the stroke width, with the
+// point on the path at the origin and the path's direction along the +x axis. Markers at the ends of
+// a path can shorten it, so that a wide stroke doesn't poke through the tip of an arrowhead.
+
+// MarkerOrient specifies how a marker is rotated.
+type MarkerOrient int
+
+const (
+	// OrientAuto rotates the marker to the path's direction, or at vertices, the bisector of the
+	// incoming and outgoing directions
+	OrientAuto MarkerOrient = iota
+	// OrientAutoStartReverse is the same as OrientAuto except at the start of the path, where the
+	// marker is reversed so that, for example, an arrowhead points away from the path
+	OrientAutoStartReverse
+	// OrientFixed rotates the marker by Angle regardless of the path
+	OrientFixed
+)
+
+// Marker contains the shape of the marker, in marker units, how it's oriented, and how far the path
+// is shortened when the marker is used at the start or end of an open path.
+type Marker struct {
+	Shape  *Shape
+	Orient MarkerOrient
+	Angle  float64 // Used with OrientFixed
+	Inset  float64 // In marker units
+}
+
+// Predefined markers. The arrowheads have their tips at the end of the path and shorten it so it
+// finishes inside the arrowhead.
+var (
+	ArrowMarker = &Marker{NewShape(Polygon(
+		[]float64{0, 0}, []float64{-4, 2}, []float64{-4, -2})), OrientAutoStartReverse, 0, 3}
+	StealthMarker = &Marker{NewShape(Polygon(
+		[]float64{0, 0}, []float64{-4, 2}, []float64{-3, 0}, []float64{-4, -2})), OrientAutoStartReverse, 0, 2.5}
+	DotMarker = &Marker{NewShape(Circle([]float64{0, 0}, 1.5)), OrientAuto, 0, 0}
+	BarMarker = &Marker{NewShape(Polygon(
+		[]float64{-0.5, -2}, []float64{0.5, -2}, []float64{0.5, 2}, []float64{-0.5, 2})), OrientAuto, 0, 0}
+	SquareMarker = &Marker{NewShape(Polygon(
+		[]float64{-1.5, -1.5}, []float64{1.5, -1.5}, []float64{1.5, 1.5}, []float64{-1.5, 1.5})), OrientAuto, 0, 0}
+	DiamondMarker = &Marker{NewShape(Polygon(
+		[]float64{2, 0}, []float64{0, 2}, []float64{-2, 0}, []float64{0, -2})), OrientAuto, 0, 0}
+)
+
+// MarkersProc strokes a path, after shortening it for the start and end markers, and adds the
+// markers, scaled by Scale, to the result. The mid marker is placed at each vertex of the path,
+// i.e. the start of every step other than the first. Any marker may be nil. As in SVG, on a closed
+// path the start and end markers are both placed at the start of the path and the path isn't
+// shortened. The markers are oriented to match the stroke so that they render as a union with it.
+type MarkersProc struct {
+	Start  *Marker
+	Mid    *Marker
+	End    *Marker
+	Scale  float64
+	Stroke PathProcessor // If nil, the shortened path is returned
+}
+
+// NewMarkersProc creates a markers path processor with the supplied stroke path processor. If the
+// stroke is a StrokeProc using TraceProcs, then the markers are scaled by the width of the stroke,
+// otherwise by 1.
+func NewMarkersProc(stroke PathProcessor, start, mid, end *Marker) MarkersProc {
+	return MarkersProc{start, mid, end, strokeWidth(stroke), stroke}
+}
+
+// NewMarkedPen returns a copy of the pen which adds the markers to the pen's stroke.
+func NewMarkedPen(pen *Pen, start, mid, end *Marker) *Pen {
+	return &Pen{pen.Filler, NewMarkersProc(pen.Stroke, start, mid, end), pen.Xfm}
+}
+
+// strokeWidth returns the width of a StrokeProc built from TraceProcs or AccurateTraceProcs, or 1.
+func strokeWidth(pp PathProcessor) float64 {
+	sp, ok := pp.(*StrokeProc)
+	if !ok {
+		return 1
+	}
+	rhs, ok1 := traceWidth(sp.RHSProc)
+	lhs, ok2 := traceWidth(sp.LHSProc)
+	if !ok1 || !ok2 {
+		return 1
+	}
+	return math.Abs(rhs) + math.Abs(lhs)
+}
+
+// traceWidth returns the width of a TraceProc or AccurateTraceProc.
+func traceWidth(pp PathProcessor) (float64, bool) {
+	switch tp := pp.(type) {
+	case TraceProc:
+		return tp.Width, true
+	case AccurateTraceProc:
+		return tp.Width, true
+	}
+	return 0, false
+}
+
+// Process implements the PathProcessor interface.
+func (mp MarkersProc) Process(p *Path) []*Path {
+	parts := p.Parts()
+	n := len(parts)
+	single := len(parts[0]) == 1
+	closed := p.Closed()
+
+	// Find the start and end points and directions
+	var sp, ep []float64
+	var sa, ea float64
+	if single {
+		sp, ep = parts[0][0], parts[0][0]
+	} else {
+		sp, ep = parts[0][0], parts[n-1][len(parts[n-1])-1]
+		sa, ea = markerAngle(parts[0], 0), markerAngle(parts[n-1], 1)
+		if closed {
+			sa = vertexAngle(parts[n-1], parts[0])
+			ep, ea = sp, sa
+		}
+	}
+
+	// Shorten and stroke the path
+	path := p
+	if !single && !closed {
+		s0, s1 := 0.0, 0.0
+		if mp.Start != nil {
+			s0 = mp.Start.Inset * mp.Scale
+		}
+		if mp.End != nil {
+			s1 = mp.End.Inset * mp.Scale
+		}
+		if s0 > 0 || s1 > 0 {
+			if l := p.ArcLength(); s0+s1 < l {
+				path = p.SubPath(p.TAtLength(s0), p.TAtLength(l-s1))
+			} else {
+				path = nil
+			}
+		}
+	}
+	res := []*Path{}
+	if path != nil {
+		if mp.Stroke != nil {
+			res = append(res, path.Process(mp.Stroke)...)
+		} else {
+			res = append(res, path)
+		}
+	}
+
+	// Match the orientation of the stroke, given by its largest outline since the inner outline of a
+	// closed path's stroke winds the other way
+	reverse := false
+	if mp.Stroke != nil {
+		ma := 0.0
+		for _, path := range res {
+			if a := path.Area(); math.Abs(a) > math.Abs(ma) {
+				ma = a
+			}
+		}
+		reverse = ma < 0
+	}
+
+	if mp.Start != nil {
+		res = append(res, mp.place(mp.Start, sp, sa, true, reverse)...)
+	}
+	if mp.Mid != nil && !single {
+		for i := 1; i < n; i++ {
+			res = append(res, mp.place(mp.Mid, parts[i][0], vertexAngle(parts[i-1], parts[i]), false, reverse)...)
+		}
+	}
+	if mp.End != nil {
+		res = append(res, mp.place(mp.End, ep, ea, false, reverse)...)
+	}
+	return res
+}
+
+// place returns the marker's paths at pt, where the path's direction is ang.
+func (mp MarkersProc) place(m *Marker, pt []float64, ang float64, start, reverse bool) []*Path {
+	if m.Shape == nil {
+		return nil
+	}
+	switch m.Orient {
+	case OrientAutoStartReverse:
+		if start {
+			ang += Pi
+		}
+	case OrientFixed:
+		ang = m.Angle
+	}
+	xfm := CreateAffineTransform(pt[0], pt[1], mp.Scale, ang)
+	shape := m.Shape.Transform(xfm)
+	paths := shape.Paths()
+	if (shape.Area() < 0) != reverse {
+		for i, path := range paths {
+			paths[i] = path.Reverse()
+		}
+	}
+	return paths
+}
+
+// markerAngle returns the direction of the part at t.
+func markerAngle(part Part, t float64) float64 {
+	dx, dy := partTangent(part, t)
+	return math.Atan2(dy, dx)
+}
+
+// vertexAngle returns the direction that bisects the end direction of p1 and the start of p2.
+func vertexAngle(p1, p2 Part) float64 {
+	dx1, dy1 := partTangent(p1, 1)
+	dx2, dy2 := partTangent(p2, 0)
+	dx, dy := dx1+dx2, dy1+dy2
+	if util.Equals(dx, 0) && util.Equals(dy, 0) {
+		// Path reverses on itself
+		return math.Atan2(dy1, dx1)
+	}
+	return math.Atan2(dy, dx)
+}
@@ -0,0 +1,42 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+	"github.com/jphsd/graphics2d/image"
+)
+
+// Demonstrates a double headed arrow. The arrowheads are scaled by the stroke width and the line is
+// shortened so it finishes inside them.
+func ExampleNewMarkedPen() {
+	pen := g2d.NewStrokedPen(color.Black, 20, g2d.JoinRound, g2d.CapButt)
+	arrow := g2d.NewMarkedPen(pen, g2d.ArrowMarker, nil, g2d.ArrowMarker)
+
+	line := g2d.Line([]float64{0, 0}, []float64{100, 0})
+	for _, path := range g2d.NewShape(line).ProcessPaths(arrow.Stroke).Paths() {
+		bb := path.BoundingBox()
+		fmt.Printf("x %.0f to %.0f, area %.0f\n", bb[0][0], bb[1][0], path.Area())
+	}
+	fmt.Println(line.HitTest([]float64{99, 0}, arrow), line.HitTest([]float64{101, 0}, arrow))
+	// Output:
+	// x 30 to 70, area 400
+	// x 0 to 40, area 800
+	// x 60 to 100, area 800
+	// true false
+}
+
+// Demonstrates mid markers on closed paths of either orientation. The markers wind the same way as
+// the outer outline of the stroke, so they add to it rather than cancel it where they overlap.
+func ExampleNewMarkersProc() {
+	square := g2d.Polygon([]float64{20, 20}, []float64{120, 20}, []float64{120, 120}, []float64{20, 120})
+	pen := g2d.NewMarkedPen(g2d.NewPen(color.Black, 8), nil, g2d.DotMarker, nil)
+
+	for _, path := range []*g2d.Path{square, square.Reverse()} {
+		img := image.NewRGBA(140, 140, color.White)
+		g2d.DrawShape(img, g2d.NewShape(path), pen)
+		r, _, _, _ := img.At(120, 20).RGBA()
+		fmt.Printf("%v, ", r < 0x8000)
+	}
+	// Output: true, true,
+}
@@ -0,0 +1,220 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Area, centroid and second moments of closed paths and shapes. These are calculated exactly from
+// the Bezier curves using Green's theorem, e.g. the area is the integral of (x dy - y dx) / 2 around
+// the path, where each integrand is formed as a Bernstein polynomial in t and integrated over the part.
+// Paths that aren't closed are treated as if they were.
+
+// Orientation describes the direction in which a closed path travels around its interior. As with
+// angles, counter-clockwise is the direction of increasing angle, i.e. from +x toward +y.
+type Orientation int
+
+// Constants for path orientation.
+const (
+	CCW Orientation = iota
+	CW
+)
+
+// String returns the name of the orientation.
+func (o Orientation) String() string {
+	if o == CW {
+		return "CW"
+	}
+	return "CCW"
+}
+
+// Moments contains the area, centroid and second moments of area of a region.
+type Moments struct {
+	Area     float64   // Signed area, positive if the region is counter-clockwise
+	Centroid []float64 // Center of mass
+	Mxx      float64   // Integral of (x-cx)^2 over the region
+	Myy      float64   // Integral of (y-cy)^2 over the region
+	Mxy      float64   // Integral of (x-cx)(y-cy) over the region
+}
+
+// PrincipalAxes returns the angle of the major principal axis of the region, and the second moments
+// about the major and minor axes. Rotating the region by -th about its centroid aligns the major axis
+// with the x axis.
+func (m Moments) PrincipalAxes() (float64, float64, float64) {
+	th := math.Atan2(2*m.Mxy, m.Mxx-m.Myy) / 2
+	mean, hd := (m.Mxx+m.Myy)/2, math.Hypot((m.Mxx-m.Myy)/2, m.Mxy)
+	return th, mean + hd, mean - hd
+}
+
+// rawMoments holds the signed integrals of 1, x, y, x^2, y^2 and xy over a region.
+type rawMoments struct {
+	a, sx, sy, sxx, syy, sxy float64
+}
+
+func (rm *rawMoments) add(o rawMoments) {
+	rm.a += o.a
+	rm.sx += o.sx
+	rm.sy += o.sy
+	rm.sxx += o.sxx
+	rm.syy += o.syy
+	rm.sxy += o.sxy
+}
+
+// moments converts the raw integrals into Moments about the centroid. The second moments are those
+// of the region regardless of its orientation.
+func (rm rawMoments) moments() Moments {
+	if rm.a == 0 {
+		return Moments{0, []float64{0, 0}, 0, 0, 0}
+	}
+	cx, cy := rm.sx/rm.a, rm.sy/rm.a
+	sgn := 1.0
+	if rm.a < 0 {
+		sgn = -1
+	}
+	return Moments{rm.a, []float64{cx, cy},
+		sgn * (rm.sxx - rm.a*cx*cx),
+		sgn * (rm.syy - rm.a*cy*cy),
+		sgn * (rm.sxy - rm.a*cx*cy)}
+}
+
+// partMoments returns the contribution of a part to the raw moments of the region it bounds.
+func partMoments(part Part) rawMoments {
+	x, y := util.BezierX(part), util.BezierY(part)
+	dx, dy := util.BernsteinDerivative(x), util.BernsteinDerivative(y)
+	integ := func(cs ...[]float64) float64 {
+		res := cs[0]
+		for _, c := range cs[1:] {
+			res = util.BernsteinMul(res, c)
+		}
+		return util.BernsteinIntegral(res)
+	}
+	xdy, ydx := integ(x, dy), integ(y, dx)
+	xxdy, yydx := integ(x, x, dy), integ(y, y, dx)
+	return rawMoments{
+		(xdy - ydx) / 2,
+		xxdy / 2,
+		-yydx / 2,
+		integ(x, x, x, dy) / 3,
+		-integ(y, y, y, dx) / 3,
+		integ(x, x, y, dy) / 2,
+	}
+}
+
+func (p *Path) rawMoments() rawMoments {
+	var res rawMoments
+	for _, part := range closedParts(p) {
+		res.add(partMoments(part))
+	}
+	return res
+}
+
+// Area returns the signed area enclosed by the path, positive if the path is counter-clockwise.
+func (p *Path) Area() float64 {
+	return p.rawMoments().a
+}
+
+// Perimeter returns the length of the path including, if it's not closed, the line closing it.
+func (p *Path) Perimeter() float64 {
+	res := p.ArcLength()
+	if !p.closed {
+		res += util.DistanceE(p.steps[0][0], p.Current())
+	}
+	return res
+}
+
+// Centroid returns the center of mass of the region enclosed by the path.
+func (p *Path) Centroid() []float64 {
+	return p.Moments().Centroid
+}
+
+// Moments returns the area, centroid and second moments of the region enclosed by the path.
+func (p *Path) Moments() Moments {
+	return p.rawMoments().moments()
+}
+
+// Orientation returns whether the path is clockwise or counter-clockwise.
+func (p *Path) Orientation() Orientation {
+	if p.Area() < 0 {
+		return CW
+	}
+	return CCW
+}
+
+// Normalize returns a new shape in which the outer contours are counter-clockwise and holes are
+// clockwise. A path is a hole if it's nested within an odd number of the shape's other paths. The
+// resultant shape renders the same under either fill rule, provided its paths don't cross.
+func (s *Shape) Normalize() *Shape {
+	n := len(s.paths)
+	polys := make([][][]float64, n)
+	for i, path := range s.paths {
+		polys[i], _ = path.PolyLine()
+	}
+	paths := make([]*Path, n)
+	for i, path := range s.paths {
+		depth := 0
+		pt := interiorTestPoint(path)
+		for j := range n {
+			if j != i && util.WindingNumber(pt, polys[j]...) != 0 {
+				depth++
+			}
+		}
+		want := CCW
+		if depth%2 == 1 {
+			want = CW
+		}
+		if path.Orientation() != want {
+			paths[i] = path.Reverse()
+		} else {
+			paths[i] = path.Copy()
+		}
+	}
+	return &Shape{paths, nil, nil, nil, s.parent, s.rule}
+}
+
+// interiorTestPoint returns a point on the path, away from its start, to test for containment.
+func interiorTestPoint(path *Path) []float64 {
+	part := path.Parts()[0]
+	if len(part) == 1 {
+		return part[0]
+	}
+	pt := util.DeCasteljau(part, 0.5)
+	return []float64{pt[0], pt[1]}
+}
+
+// Area returns the area of the region that's inside the shape under its fill rule, i.e. the area
+// that's filled when the shape is rendered.
+func (s *Shape) Area() float64 {
+	return s.rawMoments().a
+}
+
+// Perimeter returns the sum of the perimeters of the shape's paths.
+func (s *Shape) Perimeter() float64 {
+	res := 0.0
+	for _, path := range s.paths {
+		res += path.Perimeter()
+	}
+	return res
+}
+
+// Centroid returns the center of mass of the region that's inside the shape under its fill rule.
+func (s *Shape) Centroid() []float64 {
+	return s.Moments().Centroid
+}
+
+// Moments returns the area, centroid and second moments of the region that's inside the shape under
+// its fill rule.
+func (s *Shape) Moments() Moments {
+	return s.rawMoments().moments()
+}
+
+// rawMoments resolves the shape's paths under its fill rule, so that the outlines are
+// counter-clockwise and the holes clockwise, and then sums their moments.
+func (s *Shape) rawMoments() rawMoments {
+	var res rawMoments
+	rs := resolveShapes([]*Shape{s}, func(w []int) bool { return s.rule.Inside(w[0]) })
+	for _, path := range rs.paths {
+		res.add(path.rawMoments())
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"math"
+)
+
+// Demonstrates calculating the area, centroid and principal axes of a rotated rectangle, and
+// normalizing a shape with a hole so that the hole winds in the opposite direction to its outer.
+func ExamplePath_Moments() {
+	rect := g2d.Rectangle([]float64{0, 0}, 200, 100)
+	rect = rect.Process(g2d.NewAff3().Translate(50, 100).Rotate(math.Pi / 6))[0]
+	m := rect.Moments()
+	th, major, minor := m.PrincipalAxes()
+	fmt.Printf("%v area %.2f perimeter %.2f centroid %.2f,%.2f\n", rect.Orientation(), m.Area, rect.Perimeter(), m.Centroid[0], m.Centroid[1])
+	fmt.Printf("axis %.2f degrees, moments %.0f %.0f\n", th*180/math.Pi, major, minor)
+
+	// Both circles are counter-clockwise
+	ring := g2d.NewShape(g2d.Circle([]float64{0, 0}, 100), g2d.Circle([]float64{0, 0}, 50))
+	fmt.Printf("ring area %.2f\n", ring.Area())
+	fmt.Printf("evenodd ring area %.2f\n", ring.SetFillRule(g2d.EvenOdd).Area())
+	for _, path := range ring.Normalize().Paths() {
+		fmt.Printf("%v %.2f\n", path.Orientation(), path.Area())
+	}
+	// Output:
+	// CCW area 20000.00 perimeter 600.00 centroid 50.00,100.00
+	// axis 30.00 degrees, moments 66666667 16666667
+	// ring area 31416.06
+	// evenodd ring area 23562.05
+	// CCW 31416.06
+	// CW -7854.02
+}
+
+// Demonstrates that a shape's area follows its fill rule. The inner circle runs in the same direction
+// as the outer rectangle, so it's filled under NonZero but is a hole under EvenOdd.
+func ExampleShape_Area() {
+	rect := g2d.Rectangle([]float64{0, 0}, 100, 100)
+	inner := g2d.Circle([]float64{20, 0}, 20)
+	shape := g2d.NewShape(rect, inner)
+	for _, rule := range []g2d.FillRule{g2d.NonZero, g2d.EvenOdd} {
+		shape.SetFillRule(rule)
+		fmt.Printf("%v area %.1f centroid x %.2f\n", rule, shape.Area(), shape.Centroid()[0])
+	}
+	// Output:
+	// nonzero area 10000.0 centroid x 0.00
+	// evenodd area 8743.4 centroid x -2.87
+}
@@ -0,0 +1,88 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// NibStrokeProc strokes a path by sweeping a nib shape along it, as with a broad-edged pen or a
+// plotter tool, and returns the outline of the area covered (the Minkowski sum of the path and the
+// nib). The nib is positioned with its origin on the path. The nib may be non-convex and have holes.
+// Curves in both the path and the nib are flattened to within Flatten, or RenderFlatten if Flatten
+// is 0. The outlines are oriented so that the result renders the same under either fill rule.
+type NibStrokeProc struct {
+	Nib     *Shape
+	Flatten float64
+}
+
+// NewNibStrokeProc creates a nib stroke path processor using the supplied shape as the nib.
+func NewNibStrokeProc(nib *Shape) NibStrokeProc {
+	return NibStrokeProc{nib, RenderFlatten}
+}
+
+// Process implements the PathProcessor interface.
+func (np NibStrokeProc) Process(p *Path) []*Path {
+	flat := np.Flatten
+	if flat <= 0 {
+		flat = RenderFlatten
+	}
+	pieces := nibPieces(np.Nib, flat)
+	if len(pieces) == 0 {
+		return []*Path{}
+	}
+
+	// Each line of the flattened path sweeps each convex piece of the nib into the convex hull of
+	// the piece at either end of the line
+	parts := p.Flatten(flat).Parts()
+	sweeps := &Shape{}
+	for _, part := range parts {
+		a, b := part[0], part[len(part)-1]
+		for _, piece := range pieces {
+			pts := make([][]float64, 0, 2*len(piece))
+			for _, pt := range piece {
+				pts = append(pts, []float64{pt[0] + a[0], pt[1] + a[1]})
+				if len(part) > 1 {
+					pts = append(pts, []float64{pt[0] + b[0], pt[1] + b[1]})
+				}
+			}
+			sweeps.AddPaths(Polygon(util.ConvexHull(pts...)...))
+		}
+	}
+
+	// The hulls are all counter-clockwise so their union is where the winding is positive
+	res := resolveShapes([]*Shape{sweeps}, func(w []int) bool { return w[0] > 0 })
+	for _, path := range res.paths {
+		path.parent = p
+	}
+	return res.paths
+}
+
+// nibPieces returns the nib as a set of convex polygons. A convex nib is its own hull, otherwise
+// it's broken into triangles.
+func nibPieces(nib *Shape, flat float64) [][][]float64 {
+	if nib == nil {
+		return nil
+	}
+	verts, inds := nib.Triangulate(flat)
+	if len(inds) == 0 {
+		return nil
+	}
+	area := 0.0
+	tris := make([][][]float64, 0, len(inds)/3)
+	for i := 0; i < len(inds); i += 3 {
+		a, b, c := verts[inds[i]], verts[inds[i+1]], verts[inds[i+2]]
+		area += util.TriArea(a, b, c)
+		tris = append(tris, [][]float64{a, b, c})
+	}
+
+	hull := util.ConvexHull(verts...)
+	harea := 0.0
+	for i := range hull {
+		harea += util.TriArea([]float64{0, 0}, hull[i], hull[(i+1)%len(hull)])
+	}
+	if math.Abs(harea-area) <= 1e-9*harea {
+		return [][][]float64{hull}
+	}
+	return tris
+}
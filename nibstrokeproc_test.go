@@ -0,0 +1,34 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/color"
+)
+
+// Demonstrates stroking with a square nib and a non-convex L shaped nib. The square nib swept along
+// a line covers the line extended by half the nib at either end, while the L nib covers both of its
+// arms swept along the line.
+func ExampleNibStrokeProc() {
+	line := g2d.Line([]float64{0, 0}, []float64{100, 0})
+
+	square := g2d.NewShape(g2d.Polygon(
+		[]float64{-5, -5}, []float64{5, -5}, []float64{5, 5}, []float64{-5, 5}))
+	res := g2d.NewShape(line.Process(g2d.NewNibStrokeProc(square))...)
+	fmt.Printf("square nib: %d outlines, area %.2f\n", len(res.Paths()), res.Area())
+
+	ell := g2d.NewShape(g2d.Polygon(
+		[]float64{0, 0}, []float64{6, 0}, []float64{6, 2},
+		[]float64{2, 2}, []float64{2, 6}, []float64{0, 6}))
+	res = g2d.NewShape(line.Process(g2d.NewNibStrokeProc(ell))...)
+	fmt.Printf("L nib: %d outlines, area %.2f\n", len(res.Paths()), res.Area())
+
+	// As a pen's stroke
+	pen := g2d.NewPen(color.Black, 1)
+	pen.Stroke = g2d.NewNibStrokeProc(ell)
+	fmt.Println(line.HitTest([]float64{50, 5}, pen), line.HitTest([]float64{50, -1}, pen))
+	// Output:
+	// square nib: 1 outlines, area 1100.00
+	// L nib: 1 outlines, area 620.00
+	// true false
+}
@@ -0,0 +1,124 @@
+package graphics2d
+
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Offsetting (buffering) shapes. Each outline of the resolved shape has its parts displaced by the
+// offset distance on its outside. Where the displaced parts leave a gap, they're joined with the join
+// function. Where they overlap, they're connected via the original vertex instead, as in Clipper, so
+// that the area swept between the outline and its offset always winds positively. The offsets are
+// then resolved again, keeping only the areas they wind around positively. This removes the loops
+// left at tight corners, splits outlines that pinch off into separate islands and drops holes that
+// collapse. Curves that turn away from the offset side more tightly than the offset distance would
+// reverse direction when displaced, so they're flattened first.
+
+// Offset returns a new shape whose outlines are d outside of those of the shape, or inside if d is
+// negative. The join function, JoinBevel if nil, is used where the traced outline turns away from
+// the shape, e.g. JoinRound, JoinBevel or MiterJoin.JoinMiter. As with BooleanShapes, the outlines
+// are oriented so that the filled area is on the left.
+func (s *Shape) Offset(d float64, join func(Part, []float64, Part) []Part) *Shape {
+	rs := resolveShapes([]*Shape{s}, func(w []int) bool { return s.rule.Inside(w[0]) })
+	if d == 0 {
+		return rs
+	}
+	if join == nil {
+		join = JoinBevel
+	}
+
+	// With the inside on the left, the outside is on the right for both outer contours and holes
+	traced := &Shape{}
+	for _, path := range rs.paths {
+		traced.AddPaths(offsetOutline(path, d, join))
+	}
+	res := resolveShapes([]*Shape{traced}, func(w []int) bool { return w[0] > 0 })
+	res.parent = s
+	return res
+}
+
+// offsetOutline returns the closed path displaced by d on its right, or its left if d is negative.
+func offsetOutline(path *Path, d float64, join func(Part, []float64, Part) []Part) *Path {
+	w := d
+	if w < 0 {
+		// Work on the right and reverse the result afterwards
+		w = -w
+		path = path.Reverse()
+	}
+	sp := offsetSafe(path, w).Simplify()
+	parts := sp.Parts()
+	tangs := sp.Tangents()
+	rhs := offsetParts(parts, tangs, w)
+	n := len(parts)
+
+	res := make([]Part, 0, 2*n)
+	for i := range n {
+		res = append(res, rhs[i])
+		j := (i + 1) % n
+		e1, s2 := rhs[i][len(rhs[i])-1], rhs[j][0]
+		if util.EqualsP(e1, s2) {
+			continue
+		}
+		t1, t2 := tangs[i][1], tangs[j][0]
+		p := parts[j][0]
+		if t1[0]*t2[1]-t1[1]*t2[0] > 0 {
+			// Turning left, away from the offset side, so there's a gap
+			res = append(res, join(rhs[i], p, rhs[j])...)
+		} else {
+			res = append(res, Part{e1, p}, Part{p, s2})
+		}
+	}
+
+	np := PartsToPath(res...)
+	np.Close()
+	if d < 0 {
+		np = np.Reverse()
+	}
+	return np
+}
+
+// offsetSamples is the number of points sampled along a part when checking its offset.
+const offsetSamples = 16
+
+// offsetSafe returns the closed path with any curve parts that would reverse direction when traced
+// at d replaced by lines.
+func offsetSafe(path *Path, d float64) *Path {
+	parts := path.Parts()
+	res := make([]Part, 0, len(parts))
+	changed := false
+	for _, part := range parts {
+		if len(part) < 3 || !offsetReverses(part, d) {
+			res = append(res, part)
+			continue
+		}
+		res = append(res, FlattenPart(RenderFlatten, part)...)
+		changed = true
+	}
+	if !changed {
+		return path
+	}
+	np := PartsToPath(res...)
+	np.Close()
+	return np
+}
+
+// offsetReverses returns true if, at any of the sampled points, the curvature k of the part is such
+// that 1 + d * k is negative. This is the relative speed of a point offset by d to the right.
+func offsetReverses(part Part, d float64) bool {
+	d1 := toDerivative(part)
+	d2 := toDerivative(d1)
+	for i := range offsetSamples + 1 {
+		t := float64(i) / offsetSamples
+		v, a := util.DeCasteljau(d1, t), util.DeCasteljau(d2, t)
+		s2 := v[0]*v[0] + v[1]*v[1]
+		if s2 == 0 {
+			continue
+		}
+		k := (v[0]*a[1] - v[1]*a[0]) / (s2 * math.Sqrt(s2))
+		if 1+d*k < 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates offsetting an L shaped outline outward and inward. Insetting a shape far enough
+// splits it into separate pieces and eventually removes it altogether.
+func ExampleShape_Offset() {
+	ell := g2d.NewShape(g2d.Polygon(
+		[]float64{0, 0}, []float64{100, 0}, []float64{100, 30},
+		[]float64{30, 30}, []float64{30, 100}, []float64{0, 100}))
+	miter := g2d.NewMiterJoin().JoinMiter
+
+	for _, d := range []float64{10, -10, -16} {
+		res := ell.Offset(d, miter)
+		fmt.Printf("offset %.0f: %d outlines, area %.2f\n", d, len(res.Paths()), res.Area())
+	}
+
+	bar := g2d.NewShape(g2d.Polygon(
+		[]float64{0, 0}, []float64{40, 0}, []float64{40, 18}, []float64{60, 18}, []float64{60, 0},
+		[]float64{100, 0}, []float64{100, 40}, []float64{60, 40}, []float64{60, 22}, []float64{40, 22},
+		[]float64{40, 40}, []float64{0, 40}))
+	res := bar.Offset(-5, g2d.JoinRound)
+	fmt.Printf("dumbbell inset: %d outlines\n", len(res.Paths()))
+	// Output:
+	// offset 10: 1 outlines, area 9500.00
+	// offset -10: 1 outlines, area 1500.00
+	// offset -16: 0 outlines, area 0.00
+	// dumbbell inset: 2 outlines
+}
+
+// Demonstrates offsetting a shape whose curved outline is duplicated. The coincident outlines resolve
+// to a single outline both before and after they're offset.
+func ExampleShape_Offset_duplicated() {
+	circle := g2d.Circle([]float64{0, 0}, 100)
+	shape := g2d.NewShape(circle, circle.Copy())
+
+	for _, d := range []float64{0, 10, -10} {
+		res := shape.Offset(d, g2d.JoinRound)
+		fmt.Printf("offset %.0f: %d outlines, area %.2f\n", d, len(res.Paths()), res.Area())
+	}
+	// Output:
+	// offset 0: 1 outlines, area 31416.06
+	// offset 10: 1 outlines, area 38013.44
+	// offset -10: 1 outlines, area 25447.01
+}
@@ -31,11 +31,13 @@ type Path struct {
 	steps  [][][]float64
 	closed bool
 	bbox   [][]float64
+	tbbox  [][]float64
 	// Caching flattened, simplified and reversed paths, and tangents
 	flattened  *Path
 	tolerance  float64
 	simplified *Path
 	tangents   [][][]float64
+	arclen     *arcTable
 	// Processed from
 	parent *Path
 }
@@ -85,9 +87,11 @@ func (p *Path) AddStep(points ...[]float64) error {
 	p.steps = append(p.steps, nstep)
 	// Reset cached items
 	p.bbox = nil
+	p.tbbox = nil
 	p.flattened = nil
 	p.simplified = nil
 	p.tangents = nil
+	p.arclen = nil
 	return nil
 }
 
@@ -365,7 +369,7 @@ func PartLength(d float64, part Part) float64 {
 
 // BoundingBox calculates a bounding box that the Path is guaranteed to fit within. It's unlikely to
 // be the minimal bounding box for the path since the control points are also included.
-// If a tight bounding box is required then use CalcExtremities().
+// If a tight bounding box is required then use TightBoundingBox().
 func (p *Path) BoundingBox() [][]float64 {
 	if p.bbox == nil {
 		bb := [][]float64{p.steps[0][0], p.steps[0][0]}
@@ -380,11 +384,45 @@ func (p *Path) BoundingBox() [][]float64 {
 
 // Bounds calculates a rectangle that the Path is guaranteed to fit within. It's unlikely to
 // be the minimal bounding rectangle for the path since the control points are also included.
-// If a tight bounding rectangle is required then use CalcExtremities().
+// If a tight bounding rectangle is required then use TightBounds().
 func (p *Path) Bounds() image.Rectangle {
 	return util.BBToRect(p.BoundingBox())
 }
 
+// TightBoundingBox calculates the minimal bounding box of the Path. Unlike BoundingBox, the control
+// points are not included, only the end points of the parts and the extrema of the curves between them.
+func (p *Path) TightBoundingBox() [][]float64 {
+	if p.tbbox == nil {
+		bb := [][]float64{p.steps[0][0], p.steps[0][0]}
+		for _, part := range p.Parts() {
+			bbp := PartBoundingBox(part)
+			bb = util.BoundingBox(bb[0], bb[1], bbp[0], bbp[1])
+		}
+		p.tbbox = bb
+	}
+	return p.tbbox
+}
+
+// TightBounds calculates the minimal rectangle that the Path fits within.
+func (p *Path) TightBounds() image.Rectangle {
+	return util.BBToRect(p.TightBoundingBox())
+}
+
+// PartBoundingBox returns the minimal bounding box of the part, found from its end points and the
+// extremities of the curve found by util.CalcExtremities.
+func PartBoundingBox(part Part) [][]float64 {
+	last := part[len(part)-1]
+	bb := util.BoundingBox(part[0], last)
+	if len(part) < 3 {
+		return bb
+	}
+	for _, t := range util.CalcExtremities(part) {
+		pt := util.DeCasteljau(part, t)
+		bb = util.BoundingBox(bb[0], bb[1], []float64{pt[0], pt[1]})
+	}
+	return bb
+}
+
 // Copy performs a deep copy
 func (p *Path) Copy() *Path {
 	steps := make([][][]float64, len(p.steps))
@@ -785,6 +823,7 @@ func bs(pt []float64, ts, ds, te, de float64, part Part) ([]float64, float64, fl
 
 // PointInPath returns if a point is contained within a closed path according to the
 // setting of util.WindingRule. If the path is not closed then false is returned, regardless.
+// Since util.WindingRule is shared, use PointInPathRule when callers may want different rules.
 func (p *Path) PointInPath(pt []float64) bool {
 	if !p.closed {
 		return false
@@ -793,6 +832,21 @@ func (p *Path) PointInPath(pt []float64) bool {
 	return util.PointInPoly(pt, ppts...)
 }
 
+// PointInPathRule returns if a point is contained within a closed path according to the supplied
+// fill rule. Unlike PointInPath, it's not affected by the setting of util.WindingRule. If the path is
+// not closed then false is returned, regardless.
+func (p *Path) PointInPathRule(pt []float64, rule FillRule) bool {
+	if !p.closed {
+		return false
+	}
+	wr := util.NonZero
+	if rule == EvenOdd {
+		wr = util.OddEven
+	}
+	ppts, _ := p.PolyLine()
+	return util.PointInPolyRule(wr, pt, ppts...)
+}
+
 // PolyLine converts a path into a polygon line. If the second result is true, the result is a polygon.
 func (p *Path) PolyLine() ([][]float64, bool) {
 	fp := p.Flatten(RenderFlatten)
@@ -839,10 +893,12 @@ func (p *Path) UnmarshalJSON(b []byte) error {
 
 	// Reset everything else
 	p.bbox = nil
+	p.tbbox = nil
 	p.flattened = nil
 	p.tolerance = 0
 	p.simplified = nil
 	p.tangents = nil
+	p.arclen = nil
 	p.parent = nil
 
 	return nil
@@ -852,11 +908,15 @@ type xpath struct {
 	Desc string `xml:"d,attr"`
 }
 
-// MarshalXML implements the encoding/xml.Marshaler interface
+// MarshalXML implements the encoding/xml.Marshaler interface. The path is described with StringSVG.
 func (p *Path) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeElement(xpath{p.StringSVG()}, xml.StartElement{Name: xml.Name{"", "path"}})
 }
 
+// StringSVG returns the path, flattened to within DefaultRenderFlatten, as an SVG path description
+// made up of lines. It's also used by MarshalXML. The description stays a polyline, rather than
+// going through ReduceOrder, so that existing SVG output doesn't change; use StringSVGExt to keep
+// the curves.
 func (p *Path) StringSVG() string {
 	// SVG can't handle high order steps
 	fp := p.Flatten(DefaultRenderFlatten)
@@ -952,10 +1012,12 @@ func (p *Path) UnmarshalText(b []byte) error {
 
 	// Reset everything else
 	p.bbox = nil
+	p.tbbox = nil
 	p.flattened = nil
 	p.tolerance = 0
 	p.simplified = nil
 	p.tangents = nil
+	p.arclen = nil
 	p.parent = nil
 
 	return nil
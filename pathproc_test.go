@@ -0,0 +1,55 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates snipping a path into alternating pieces with a pattern. The pattern lengths are
+// measured along the curve, so each piece of the quarter circle below spans the same angle.
+func ExampleSnipProc() {
+	arc := g2d.PartsToPath(g2d.MakeArcParts(0, 0, 100, 0, g2d.HalfPi)...)
+	quarter := arc.ArcLength()
+
+	sp := g2d.NewSnipProc(2, []float64{quarter / 3, quarter / 6}, 0)
+	for _, piece := range arc.Process(sp) {
+		pt := piece.Current()
+		fmt.Printf("%.2f %.2f length %.2f\n", pt[0], pt[1], piece.ArcLength())
+	}
+	// Output:
+	// 86.60 50.00 length 52.36
+	// 70.71 70.71 length 26.18
+	// 25.88 96.59 length 52.36
+	// 0.00 100.00 length 26.18
+}
+
+// Demonstrates replacing a path with a wave. Each half wave or scallop spans the same distance
+// along the path, so the waves keep step around curves.
+func ExampleTriangleWaveProc() {
+	arc := g2d.PartsToPath(g2d.MakeArcParts(0, 0, 100, 0, g2d.HalfPi)...)
+	lambda := arc.ArcLength() / 2
+
+	for _, step := range arc.Process(g2d.NewTriangleWaveProc(lambda, 10))[0].Steps() {
+		pt := step[len(step)-1]
+		fmt.Printf("%.2f,%.2f\n", pt[0], pt[1])
+	}
+
+	procs := []g2d.PathProcessor{
+		g2d.NewSquareWaveProc(lambda, 10),
+		g2d.ScallopProc{Lambda: lambda / 2},
+	}
+	for _, proc := range procs {
+		wave := arc.Process(proc)[0]
+		start, end := wave.Steps()[0][0], wave.Current()
+		fmt.Printf("%d steps from %.2f,%.2f to %.2f,%.2f\n", len(wave.Steps()), start[0], start[1], end[0], end[1])
+	}
+	// Output:
+	// 100.00,0.00
+	// 91.32,18.16
+	// 85.68,57.25
+	// 51.73,77.42
+	// 20.10,101.07
+	// 0.00,100.00
+	// 10 steps from 100.00,0.00 to 0.00,100.00
+	// 21 steps from 100.00,0.00 to -0.12,100.02
+}
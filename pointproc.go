@@ -34,7 +34,8 @@ func NewPointsProc(shapes []*Shape, rot PointRot) PointsProc {
 
 // Process implements the PathProcessor interface.
 func (pp PointsProc) Process(p *Path) []*Path {
-	parts := p.Parts()
+	at := p.arcLengths()
+	parts := at.parts
 	n := len(parts)
 	if !p.Closed() {
 		n++
@@ -42,8 +43,8 @@ func (pp PointsProc) Process(p *Path) []*Path {
 	res := make([]*Path, 0, n)
 	ns := len(pp.Points)
 	cp := 0
-	for _, part := range parts {
-		if partLen(part) < 0.0001 {
+	for i, part := range parts {
+		if at.starts[i+1]-at.starts[i] < 0.0001 {
 			// Skip 0 length parts which cause tangent issues
 			continue
 		}
@@ -117,14 +118,10 @@ func NewShapesProc(shapes []*Shape, spacing float64, rot PointRot) ShapesProc {
 
 // Process implements the PathProcessor interface.
 func (sp ShapesProc) Process(p *Path) []*Path {
+	if len(p.steps) == 0 {
+		return nil
+	}
 	path := p.Process(sp.Comp)[0]
 
 	return path.Process(sp.Shapes)
 }
-
-func partLen(part [][]float64) float64 {
-	p1 := part[0]
-	p2 := part[len(part)-1]
-	dx, dy := p2[0]-p1[0], p2[1]-p1[1]
-	return math.Hypot(dx, dy)
-}
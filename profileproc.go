@@ -0,0 +1,266 @@
+package graphics2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Variable width tracing and stroking. The offset of a part by a width w that varies with the
+// normalized arc length of the path has, for the part's unit tangent T, RHS normal N, curvature k
+// and speed v, the derivative v * ((1 + w * k) * T + dw/ds * N). Each part is offset by the cubic
+// with the exact end points and end derivatives of the true offset (a Hermite approximation),
+// subdividing the part until the cubic is within the tolerance of it, so curves stay smooth.
+
+// ProfileTraceProc traces a path at a normal distance from it given by Func(t), where t is the
+// normalized arc length along the path, [0,1]. Positive distances are to the right of the path and
+// negative ones to the left. Where the path has corners, the offsets are joined with JoinFunc.
+type ProfileTraceProc struct {
+	Func      func(float64) float64
+	JoinFunc  func(Part, []float64, Part) []Part
+	Tolerance float64
+}
+
+// DefaultProfileTolerance is used by NewProfileStrokeProc if no tolerance is supplied.
+const DefaultProfileTolerance = 0.1
+
+// NewProfileStrokeProc creates a stroke path processor whose width on each side of the path, lw and
+// rw, is given by profile(t), where t is the normalized arc length along the path, [0,1]. This
+// allows for tapered, swelling and pressure sensitive strokes. If d is greater than 0, then the
+// sides are traced to within d, otherwise DefaultProfileTolerance is used. The join function is
+// used at corners in the path and the cap function at the ends of open paths.
+func NewProfileStrokeProc(profile func(float64) (float64, float64),
+	jf func(Part, []float64, Part) []Part,
+	d float64,
+	cf func(Part, []float64, Part) []Part) *StrokeProc {
+	if d <= 0 {
+		d = DefaultProfileTolerance
+	}
+	if jf == nil {
+		jf = JoinBevel
+	}
+	if cf == nil {
+		cf = CapButt
+	}
+	lw, rw := profile(0)
+	return &StrokeProc{
+		RHSProc: ProfileTraceProc{func(t float64) float64 {
+			_, rw := profile(t)
+			return rw
+		}, jf, d},
+		LHSProc: ProfileTraceProc{func(t float64) float64 {
+			lw, _ := profile(t)
+			return -lw
+		}, jf, d},
+		PostSideProc: nil,
+		Width:        lw + rw,
+		PointFunc:    PointCircle,
+		CapStartFunc: cf,
+		CapEndFunc:   cf,
+	}
+}
+
+// SampledProfile returns a profile function, suitable for NewProfileStrokeProc, that interpolates
+// the samples, each of which is {t, lw, rw}, with a monotone cubic so the widths change smoothly
+// without overshooting the samples. The samples are sorted by t and the profile is constant beyond
+// the first and last of them.
+func SampledProfile(samples ...[]float64) func(float64) (float64, float64) {
+	n := len(samples)
+	if n == 0 {
+		return func(float64) (float64, float64) { return 0, 0 }
+	}
+	ss := make([][]float64, n)
+	copy(ss, samples)
+	sort.SliceStable(ss, func(i, j int) bool { return ss[i][0] < ss[j][0] })
+	ts := make([]float64, n)
+	lws, rws := make([]float64, n), make([]float64, n)
+	for i, s := range ss {
+		ts[i], lws[i], rws[i] = s[0], s[1], s[2]
+	}
+	lds, rds := monotoneSlopes(ts, lws), monotoneSlopes(ts, rws)
+
+	return func(t float64) (float64, float64) {
+		if t <= ts[0] {
+			return lws[0], rws[0]
+		}
+		if t >= ts[n-1] {
+			return lws[n-1], rws[n-1]
+		}
+		i := sort.SearchFloat64s(ts, t) - 1
+		h := ts[i+1] - ts[i]
+		if h == 0 {
+			return lws[i+1], rws[i+1]
+		}
+		u := (t - ts[i]) / h
+		return hermite(lws[i], lws[i+1], lds[i]*h, lds[i+1]*h, u),
+			hermite(rws[i], rws[i+1], rds[i]*h, rds[i+1]*h, u)
+	}
+}
+
+// monotoneSlopes returns the Fritsch-Carlson slopes at the samples for a monotone cubic interpolation.
+func monotoneSlopes(ts, vs []float64) []float64 {
+	n := len(ts)
+	ds := make([]float64, n)
+	if n < 2 {
+		return ds
+	}
+	secs := make([]float64, n-1)
+	for i := range n - 1 {
+		if h := ts[i+1] - ts[i]; h > 0 {
+			secs[i] = (vs[i+1] - vs[i]) / h
+		}
+	}
+	ds[0], ds[n-1] = secs[0], secs[n-2]
+	for i := 1; i < n-1; i++ {
+		if secs[i-1]*secs[i] > 0 {
+			ds[i] = (secs[i-1] + secs[i]) / 2
+		}
+	}
+	for i, sec := range secs {
+		if sec == 0 {
+			ds[i], ds[i+1] = 0, 0
+			continue
+		}
+		a, b := ds[i]/sec, ds[i+1]/sec
+		if h := math.Hypot(a, b); h > 3 {
+			ds[i], ds[i+1] = 3*a/h*sec, 3*b/h*sec
+		}
+	}
+	return ds
+}
+
+// hermite evaluates the cubic Hermite basis at u for end values v0, v1 and end slopes d0, d1.
+func hermite(v0, v1, d0, d1, u float64) float64 {
+	u2 := u * u
+	u3 := u2 * u
+	return (2*u3-3*u2+1)*v0 + (u3-2*u2+u)*d0 + (-2*u3+3*u2)*v1 + (u3-u2)*d1
+}
+
+// Process implements the PathProcessor interface.
+func (pp ProfileTraceProc) Process(p *Path) []*Path {
+	path := PartsToPath(pp.ProcessParts(p)...)
+	if path == nil {
+		return []*Path{}
+	}
+	if p.Closed() {
+		path.Close()
+	}
+	return []*Path{path}
+}
+
+// ProcessParts returns the processed path as a slice of parts.
+func (pp ProfileTraceProc) ProcessParts(p *Path) []Part {
+	// A point isn't traceable.
+	if len(p.Steps()) == 1 {
+		return []Part{}
+	}
+	tol := pp.Tolerance
+	if tol <= 0 {
+		tol = DefaultProfileTolerance
+	}
+
+	p = p.Simplify()
+	parts := p.Parts()
+	at := newArcTable(parts)
+	pt := &profileTrace{pp.Func, at, at.starts[len(parts)], tol}
+	nparts, rhs := make([]Part, 0, len(parts)), make([]Part, 0, len(parts))
+	for i, part := range parts {
+		nparts, rhs = pt.offsetPart(part, i, 0, 1, 0, nparts, rhs)
+	}
+	jf := pp.JoinFunc
+	if jf == nil {
+		jf = JoinBevel
+	}
+	return joinOffsets(nparts, rhs, p.Closed(), jf, trimKnot)
+}
+
+// profileTrace holds the state needed to offset the parts of a path by a width profile.
+type profileTrace struct {
+	f     func(float64) float64
+	at    *arcTable
+	total float64
+	tol   float64
+}
+
+// profileDelta is the step in normalized arc length used to find the slope of the profile.
+const profileDelta = 1e-4
+
+// width returns the width and its derivative with respect to arc length, at t in part i.
+func (pt *profileTrace) width(i int, t float64) (float64, float64) {
+	if pt.total == 0 {
+		return pt.f(0), 0
+	}
+	s := (pt.at.starts[i] + pt.at.length(i, t)) / pt.total
+	s0, s1 := math.Max(0, s-profileDelta), math.Min(1, s+profileDelta)
+	return pt.f(s), (pt.f(s1) - pt.f(s0)) / ((s1 - s0) * pt.total)
+}
+
+// offsetPart appends part, which spans t0 to t1 of part i of the path, and its offset, subdividing
+// the part as necessary to get the offset within the tolerance.
+func (pt *profileTrace) offsetPart(part Part, i int, t0, t1 float64, depth int, parts, rhs []Part) ([]Part, []Part) {
+	w0, ws0 := pt.width(i, t0)
+	w1, ws1 := pt.width(i, t1)
+	if len(part) == 2 && util.Equals(w0, w1) && util.Equals(ws0, 0) && util.Equals(ws1, 0) {
+		return append(parts, part), append(rhs, offsetLine(part, w0))
+	}
+
+	d1 := toDerivative(part)
+	var d2 Part
+	if len(d1) > 1 {
+		d2 = toDerivative(d1)
+	}
+	q0, v0 := profileOffsetAt(part, d1, d2, 0, w0, ws0)
+	q1, v1 := profileOffsetAt(part, d1, d2, 1, w1, ws1)
+	off := Part{q0, {q0[0] + v0[0]/3, q0[1] + v0[1]/3}, {q1[0] - v1[0]/3, q1[1] - v1[1]/3}, q1}
+	if depth == maxOffsetDepth {
+		return append(parts, part), append(rhs, off)
+	}
+
+	// Compare the true offset with the cubic at the sample points
+	ok := true
+	for j := 1; j < offsetSamples && ok; j++ {
+		u := float64(j) / offsetSamples
+		w, ws := pt.width(i, t0+u*(t1-t0))
+		q, _ := profileOffsetAt(part, d1, d2, u, w, ws)
+		_, d := util.ClosestT(off, q)
+		ok = d <= pt.tol*pt.tol
+	}
+	if ok {
+		return append(parts, part), append(rhs, off)
+	}
+	l, r := splitPart(part, 0.5)
+	tm := (t0 + t1) / 2
+	parts, rhs = pt.offsetPart(l, i, t0, tm, depth+1, parts, rhs)
+	return pt.offsetPart(r, i, tm, t1, depth+1, parts, rhs)
+}
+
+// profileOffsetAt returns the point at t on the part offset by w along its RHS normal and the
+// derivative there, given the part's first and second derivatives and the rate of change of w with
+// respect to arc length.
+func profileOffsetAt(part, d1, d2 Part, t, w, ws float64) ([]float64, []float64) {
+	p := util.DeCasteljau(part, t)
+	var v, a []float64
+	if len(d1) == 1 {
+		v, a = d1[0], []float64{0, 0}
+	} else {
+		v = util.DeCasteljau(d1, t)
+		if len(d2) == 1 {
+			a = d2[0]
+		} else {
+			a = util.DeCasteljau(d2, t)
+		}
+	}
+	s := math.Hypot(v[0], v[1])
+	if s == 0 {
+		// Degenerate derivative, offset along the normal to the part's direction
+		dir := partDirection(part, t > 0.5)
+		dx, dy := unit(dir[0], dir[1])
+		return []float64{p[0] + w*dy, p[1] - w*dx}, []float64{0, 0}
+	}
+	tx, ty := v[0]/s, v[1]/s
+	nx, ny := ty, -tx
+	k := (v[0]*a[1] - v[1]*a[0]) / (s * s * s)
+	f := 1 + w*k
+	return []float64{p[0] + w*nx, p[1] + w*ny}, []float64{s * (f*tx + ws*nx), s * (f*ty + ws*ny)}
+}
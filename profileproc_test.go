@@ -0,0 +1,30 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates a stroke that tapers from a width of 20 to a point along a line, and a profile
+// interpolated from pressure samples.
+func ExampleNewProfileStrokeProc() {
+	line := g2d.Line([]float64{0, 0}, []float64{100, 0})
+	taper := func(t float64) (float64, float64) {
+		return 10 * (1 - t), 10 * (1 - t)
+	}
+	res := g2d.NewShape(line.Process(g2d.NewProfileStrokeProc(taper, nil, 0, nil))...)
+	fmt.Printf("taper: %d outlines, area %.2f\n", len(res.Paths()), res.Area())
+
+	pressure := g2d.SampledProfile(
+		[]float64{0, 0, 0}, []float64{0.25, 4, 4}, []float64{0.75, 4, 4}, []float64{1, 0, 0})
+	for _, t := range []float64{0, 0.125, 0.5, 1} {
+		lw, rw := pressure(t)
+		fmt.Printf("%.3f: %.2f %.2f\n", t, lw, rw)
+	}
+	// Output:
+	// taper: 1 outlines, area 1000.00
+	// 0.000: 0.00 0.00
+	// 0.125: 2.50 2.50
+	// 0.500: 4.00 4.00
+	// 1.000: 0.00 0.00
+}
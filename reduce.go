@@ -0,0 +1,209 @@
+package graphics2d
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Degree reduction of path steps. A step of too high an order is replaced by a curve of the target
+// order with the same end points and end tangents. For cubics, if the curve with the same end
+// derivatives isn't within the tolerance of the original, then a least squares fit is tried. If the
+// replacement still isn't within the tolerance, then the step is split in half and each half reduced
+// in turn.
+
+// maxReduceDepth bounds the subdivision of a step during reduction.
+const maxReduceDepth = 16
+
+// ReduceOrder returns a new path where steps with an order greater than order, which must be 2
+// (quadratic) or 3 (cubic), are replaced by a sequence of quadratic or cubic steps within tol of the
+// original step.
+func (p *Path) ReduceOrder(order int, tol float64) *Path {
+	order = max(2, min(order, 3))
+	np := NewPath(copyPoint(p.steps[0][0]))
+	for _, part := range p.stepParts() {
+		for _, rp := range reducePart(part, order, tol) {
+			np.AddStep(rp[1:]...)
+		}
+	}
+	if p.closed {
+		np.Close()
+	}
+	np.parent = p
+	return np
+}
+
+// stepParts returns the steps of the path as parts. Unlike Parts(), no closing line is added.
+func (p *Path) stepParts() []Part {
+	n := len(p.steps)
+	if n < 2 {
+		return nil
+	}
+	res := make([]Part, n-1)
+	cp := p.steps[0][0]
+	for i := 1; i < n; i++ {
+		res[i-1] = toPart(cp, p.steps[i])
+		cp = p.steps[i][len(p.steps[i])-1]
+	}
+	return res
+}
+
+// reducePart returns a sequence of parts of at most the given order approximating part within tol.
+func reducePart(part Part, order int, tol float64) []Part {
+	n := len(part) - 1
+	if n <= order {
+		return []Part{part}
+	}
+	if order == 2 && n > 3 {
+		// Go via cubics, splitting the tolerance between the two stages
+		res := []Part{}
+		for _, cp := range reducePart(part, 3, tol/2) {
+			res = append(res, reducePart(cp, 2, tol/2)...)
+		}
+		return res
+	}
+	return reducePartR(part, order, tol, 0, nil)
+}
+
+func reducePartR(part Part, order int, tol float64, depth int, res []Part) []Part {
+	var approx Part
+	var err float64
+	if order == 3 {
+		approx = hermiteCubic(part)
+		err = partError(part, approx)
+		if err > tol {
+			// Try a least squares fit
+			if lsq := fitCubic(part); lsq != nil {
+				if lerr := partError(part, lsq); lerr < err {
+					approx, err = lsq, lerr
+				}
+			}
+		}
+	} else {
+		approx = midpointQuad(part)
+		err = partError(part, approx)
+	}
+	if depth == maxReduceDepth || err <= tol {
+		return append(res, approx)
+	}
+	l, r := splitPart(part, 0.5)
+	res = reducePartR(l, order, tol, depth+1, res)
+	return reducePartR(r, order, tol, depth+1, res)
+}
+
+// hermiteCubic returns the cubic with the same end points and end derivatives as the part.
+func hermiteCubic(part Part) Part {
+	n := len(part) - 1
+	s := float64(n) / 3
+	p0, p1, pn1, pn := part[0], part[1], part[n-1], part[n]
+	return Part{
+		{p0[0], p0[1]},
+		{p0[0] + s*(p1[0]-p0[0]), p0[1] + s*(p1[1]-p0[1])},
+		{pn[0] + s*(pn1[0]-pn[0]), pn[1] + s*(pn1[1]-pn[1])},
+		{pn[0], pn[1]},
+	}
+}
+
+// fitCubic returns the cubic with the same end points and end tangent directions as the part whose
+// control point distances minimize the squared error at sampled t values, or nil if there's no
+// such cubic.
+func fitCubic(part Part) Part {
+	n := len(part) - 1
+	p0, p3 := part[0], part[n]
+	d0 := []float64{part[1][0] - p0[0], part[1][1] - p0[1]}
+	d1 := []float64{part[n-1][0] - p3[0], part[n-1][1] - p3[1]}
+	var a11, a12, a22, r1, r2 float64
+	for i := 1; i < reduceSamples; i++ {
+		t := float64(i) / reduceSamples
+		mt := 1 - t
+		b0, b1, b2, b3 := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		q := util.DeCasteljau(part, t)
+		rx := q[0] - (b0+b1)*p0[0] - (b2+b3)*p3[0]
+		ry := q[1] - (b0+b1)*p0[1] - (b2+b3)*p3[1]
+		a11 += b1 * b1 * (d0[0]*d0[0] + d0[1]*d0[1])
+		a12 += b1 * b2 * (d0[0]*d1[0] + d0[1]*d1[1])
+		a22 += b2 * b2 * (d1[0]*d1[0] + d1[1]*d1[1])
+		r1 += b1 * (d0[0]*rx + d0[1]*ry)
+		r2 += b2 * (d1[0]*rx + d1[1]*ry)
+	}
+	det := a11*a22 - a12*a12
+	if math.Abs(det) < 1e-12 {
+		return nil
+	}
+	a, b := (r1*a22-r2*a12)/det, (a11*r2-a12*r1)/det
+	if a <= 0 || b <= 0 {
+		return nil
+	}
+	return Part{
+		{p0[0], p0[1]},
+		{p0[0] + a*d0[0], p0[1] + a*d0[1]},
+		{p3[0] + b*d1[0], p3[1] + b*d1[1]},
+		{p3[0], p3[1]},
+	}
+}
+
+// midpointQuad returns the quadratic that best approximates a cubic, with its control point at the
+// mean of the two extrapolated end tangent points.
+func midpointQuad(part Part) Part {
+	p0, p1, p2, p3 := part[0], part[1], part[2], part[3]
+	return Part{
+		{p0[0], p0[1]},
+		{(3*(p1[0]+p2[0]) - p0[0] - p3[0]) / 4, (3*(p1[1]+p2[1]) - p0[1] - p3[1]) / 4},
+		{p3[0], p3[1]},
+	}
+}
+
+// reduceSamples is the number of points compared in each direction when measuring the error between
+// a part and its approximation.
+const reduceSamples = 16
+
+// partError returns the maximum distance found between sampled points on either part and the other part.
+func partError(a, b Part) float64 {
+	md := 0.0
+	for i := 1; i < reduceSamples; i++ {
+		t := float64(i) / reduceSamples
+		_, d1 := util.ClosestT(b, util.DeCasteljau(a, t))
+		_, d2 := util.ClosestT(a, util.DeCasteljau(b, t))
+		md = math.Max(md, math.Max(d1, d2))
+	}
+	return math.Sqrt(md)
+}
+
+// ReduceOrderProc is a wrapper around Path.ReduceOrder() and contains the maximum order of the
+// resultant steps, 2 or 3, and the tolerance.
+type ReduceOrderProc struct {
+	Order     int
+	Tolerance float64
+}
+
+// Process implements the PathProcessor interface.
+func (rp ReduceOrderProc) Process(p *Path) []*Path {
+	return []*Path{p.ReduceOrder(rp.Order, rp.Tolerance)}
+}
+
+// StringSVGExt returns the path as an SVG path description. Unlike StringSVG, the path isn't flattened.
+// Instead steps of order greater than 3 are reduced to cubics within tol so that the curves can be
+// described with SVG's quadratic and cubic commands.
+func (p *Path) StringSVGExt(tol float64) string {
+	rp := p.ReduceOrder(3, tol)
+	pt := rp.steps[0][0]
+	desc := fmt.Sprintf("M %.2f %.2f", pt[0], pt[1])
+	for _, step := range rp.steps[1:] {
+		switch len(step) {
+		case 1:
+			desc += " L"
+		case 2:
+			desc += " Q"
+		default:
+			desc += " C"
+		}
+		for _, pt := range step {
+			desc += fmt.Sprintf(" %.2f %.2f", pt[0], pt[1])
+		}
+	}
+	if p.closed {
+		desc += " z"
+	}
+	return desc
+}
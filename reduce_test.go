@@ -0,0 +1,23 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates reducing a quintic step to cubics and quadratics for export.
+func ExamplePath_ReduceOrder() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{50, 200}, []float64{100, -150}, []float64{150, 200}, []float64{200, -100}, []float64{250, 0})
+
+	for _, tol := range []float64{1, 0.1} {
+		cubics := path.ReduceOrder(3, tol)
+		quads := path.Process(g2d.ReduceOrderProc{2, tol})[0]
+		fmt.Printf("tolerance %.1f: %d cubics, %d quads\n", tol, len(cubics.Steps())-1, len(quads.Steps())-1)
+	}
+	fmt.Println(path.StringSVGExt(1))
+	// Output:
+	// tolerance 1.0: 4 cubics, 9 quads
+	// tolerance 0.1: 8 cubics, 21 quads
+	// M 0.00 0.00 C 19.74 78.97 42.92 65.15 62.50 55.66 C 83.33 45.57 104.17 36.46 125.00 31.25 C 145.83 26.04 166.67 18.23 187.50 2.93 C 206.65 -11.14 230.80 -38.41 250.00 0.00
+}
@@ -35,13 +35,31 @@ const DefaultRenderFlatten = 0.6
 var RenderFlatten = DefaultRenderFlatten
 
 // RenderShapeExt renders the supplied shape with the fill and clip images into
-// the destination image region using op.
+// the destination image region using op. The shape's fill rule is honored.
 func RenderShapeExt(dst draw.Image, drect image.Rectangle, shape *Shape, filler image.Image, fp image.Point, mask *image.Alpha, mp image.Point, op draw.Op) {
+	renderShapeExt(dst, drect, shape.Bounds(), shape, filler, fp, mask, mp, op)
+}
+
+// RenderShapeTight renders the supplied shape with the fill image into the destination image, as for
+// RenderShape, but sizes the rasterizer from the shape's tight bounds rather than from bounds that
+// include the control points of its curves.
+func RenderShapeTight(dst draw.Image, shape *Shape, filler image.Image) {
+	r := dst.Bounds()
+	RenderShapeExtTight(dst, r, shape, filler, r.Min, nil, image.Point{}, draw.Over)
+}
+
+// RenderShapeExtTight is RenderShapeExt with the rasterizer sized from the shape's tight bounds.
+func RenderShapeExtTight(dst draw.Image, drect image.Rectangle, shape *Shape, filler image.Image, fp image.Point, mask *image.Alpha, mp image.Point, op draw.Op) {
+	renderShapeExt(dst, drect, shape.TightBounds(), shape, filler, fp, mask, mp, op)
+}
+
+// renderShapeExt is RenderShapeExt with the bounds of the shape, srect, supplied by the caller so
+// that the tight bounds can be used instead.
+func renderShapeExt(dst draw.Image, drect, srect image.Rectangle, shape *Shape, filler image.Image, fp image.Point, mask *image.Alpha, mp image.Point, op draw.Op) {
 	orig := drect.Min
 
 	// To avoid unnecessary work, reduce the rasterizer size to the shape width and height
 	// clipped by the destination image bounds, the filler image and the clip image
-	srect := shape.Bounds()
 	drect = drect.Intersect(srect)
 	// the filler bounds
 	drect = drect.Intersect(filler.Bounds().Add(orig.Sub(fp)))
@@ -56,6 +74,11 @@ func RenderShapeExt(dst draw.Image, drect image.Rectangle, shape *Shape, filler
 	size := drect.Size()
 	dx, dy := drect.Min.X-orig.X, drect.Min.Y-orig.Y
 
+	if shape.rule == EvenOdd {
+		renderShapeEvenOdd(dst, drect, shape, filler, fp.Add(image.Point{dx, dy}), mask, mp.Add(image.Point{dx, dy}), op)
+		return
+	}
+
 	// Make rasterizer, note rasterizer has implicit r.Min of {0, 0}
 	rasterizer := vector.NewRasterizer(size.X, size.Y)
 	rasterizer.DrawOp = op
@@ -94,3 +117,25 @@ func RenderShapeExt(dst draw.Image, drect image.Rectangle, shape *Shape, filler
 	rasterizer.Draw(nmask, drect, mask, mp)
 	draw.DrawMask(dst, drect, filler, fp, nmask, drect.Min, op)
 }
+
+// renderShapeEvenOdd is the even-odd equivalent of the rasterizer code in renderShapeExt. drect has
+// already been clipped and fp and mp adjusted to it.
+func renderShapeEvenOdd(dst draw.Image, drect image.Rectangle, shape *Shape, filler image.Image, fp image.Point, mask *image.Alpha, mp image.Point, op draw.Op) {
+	nmask := renderEvenOdd(shape.paths, drect)
+	if mask != nil {
+		// Intersect it against the clip mask
+		for y := drect.Min.Y; y < drect.Max.Y; y++ {
+			off := nmask.PixOffset(drect.Min.X, y)
+			my := mp.Y + y - drect.Min.Y
+			for x := 0; x < drect.Dx(); x++ {
+				a := uint32(nmask.Pix[off+x])
+				if a == 0 {
+					continue
+				}
+				ma := uint32(mask.AlphaAt(mp.X+x, my).A)
+				nmask.Pix[off+x] = uint8((a*ma + 0x7f) / 0xff)
+			}
+		}
+	}
+	draw.DrawMask(dst, drect, filler, fp, nmask, drect.Min, op)
+}
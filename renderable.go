@@ -8,10 +8,14 @@ import (
 
 // Renderable represents a set of shapes and the images to fill them. In other words, enough information to be
 // able to render something. This structure is used to build complex multicolored objects in a composable way.
+//
+// If TightBounds is set, then the shapes' tight bounds are used by Bounds and to size the rasterizer
+// when rendering, rather than the bounds including their control points.
 type Renderable struct {
-	Shapes  []*Shape
-	Clips   []*Shape
-	Fillers []image.Image
+	Shapes      []*Shape
+	Clips       []*Shape
+	Fillers     []image.Image
+	TightBounds bool
 }
 
 // NewRenderable creates a new instance with the given shape and filler image.
@@ -61,7 +65,7 @@ func (r *Renderable) AddPennedShape(shape *Shape, pen *Pen, xfm Transform) *Rend
 // AddClippedPennedShape adds the given shape, clip and pen to the Renderable after being transformed.
 func (r *Renderable) AddClippedPennedShape(shape, clip *Shape, pen *Pen, xfm Transform) *Renderable {
 	if pen.Stroke != nil {
-		shape = shape.ProcessPaths(pen.Stroke)
+		shape = shape.ProcessPaths(pen.Stroke).SetFillRule(NonZero)
 	}
 	if pen.Xfm != nil {
 		shape = shape.Transform(pen.Xfm)
@@ -83,16 +87,12 @@ func (r *Renderable) Render(img draw.Image, xfm Transform) {
 	for i, shape := range r.Shapes {
 		clip := r.Clips[i]
 		if xfm != nil {
-			if clip == nil {
-				RenderShape(img, shape.Transform(xfm), r.Fillers[i])
-			} else {
-				RenderClippedShape(img, shape.Transform(xfm), clip.Transform(xfm), r.Fillers[i])
+			shape = shape.Transform(xfm)
+			if clip != nil {
+				clip = clip.Transform(xfm)
 			}
-		} else if clip == nil {
-			RenderShape(img, shape, r.Fillers[i])
-		} else {
-			RenderClippedShape(img, shape, clip, r.Fillers[i])
 		}
+		r.render(img, shape, clip, r.Fillers[i])
 	}
 }
 
@@ -105,21 +105,34 @@ func (r *Renderable) Image() *image.RGBA {
 	rect := r.Bounds()
 	img := image.NewRGBA(rect)
 	for i, shape := range r.Shapes {
-		clip := r.Clips[i]
-		if clip == nil {
-			RenderShape(img, shape, r.Fillers[i])
-		} else {
-			RenderClippedShape(img, shape, clip, r.Fillers[i])
-		}
+		r.render(img, shape, r.Clips[i], r.Fillers[i])
 	}
 	return img
 }
 
+// render renders the shape with the filler, masked by the clip shape if it's not nil, into the image.
+func (r *Renderable) render(img draw.Image, shape, clip *Shape, filler image.Image) {
+	rect := img.Bounds()
+	var mask *image.Alpha
+	if clip != nil {
+		mask = clip.Mask()
+	}
+	renderShapeExt(img, rect, r.shapeBounds(shape), shape, filler, rect.Min, mask, rect.Min, draw.Over)
+}
+
 // Bounds returns the extent of the renderable.
 func (r *Renderable) Bounds() image.Rectangle {
 	rect := image.Rectangle{}
 	for _, shape := range r.Shapes {
-		rect = rect.Union(shape.Bounds())
+		rect = rect.Union(r.shapeBounds(shape))
 	}
 	return rect
 }
+
+// shapeBounds returns the bounds of the shape, tight if TightBounds is set.
+func (r *Renderable) shapeBounds(shape *Shape) image.Rectangle {
+	if r.TightBounds {
+		return shape.TightBounds()
+	}
+	return shape.Bounds()
+}
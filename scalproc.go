@@ -10,9 +10,8 @@ type ScallopProc struct {
 
 // Process implements the PathProcessor interface.
 func (sp ScallopProc) Process(p *Path) []*Path {
-	// Chunk up path into pieces
-	pp1 := NewMunchProc(sp.Lambda).Process(p)
-	p1, _ := ConcatenatePaths(pp1...)
+	// Points along the path, a scallop length apart
+	pts := p.pointsEvery(sp.Lambda)
 	var arc *Path
 	if sp.Flip {
 		arc = PartsToPath(MakeArcParts(0, 0, sp.Lambda/2, Pi, -Pi)...)
@@ -20,12 +19,15 @@ func (sp ScallopProc) Process(p *Path) []*Path {
 		arc = PartsToPath(MakeArcParts(0, 0, sp.Lambda/2, Pi, Pi)...)
 	}
 
-	n := len(p1.steps)
-	last := p1.steps[0][0]
+	n := len(pts)
+	if n == 0 {
+		return []*Path{p}
+	}
+	last := pts[0]
 	path := NewPath(last)
 	for i := 1; i < n; i++ {
 		// Each step is a scallop
-		cur := p1.steps[i][0]
+		cur := pts[i]
 		dx, dy := cur[0]-last[0], cur[1]-last[1]
 		th := math.Atan2(dy, dx)
 		cx, cy := (cur[0]+last[0])/2, (cur[1]+last[1])/2
@@ -12,11 +12,70 @@ import (
 
 // Shape is a fillable collection of paths. For a path to be fillable,
 // it must be closed, so paths added to the shape are forced closed on rendering.
+// The shape's fill rule determines which regions formed by the paths are inside the shape.
 type Shape struct {
 	paths  []*Path
 	bbox   [][]float64
+	tbbox  [][]float64
 	mask   *image.Alpha
 	parent *Shape
+	rule   FillRule
+}
+
+// FillRule determines how the winding numbers of the shape's paths about a point are combined to
+// decide whether the point is inside the shape.
+type FillRule int
+
+// Constants for fill rules. NonZero is the default.
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// String returns the SVG name of the fill rule.
+func (fr FillRule) String() string {
+	if fr == EvenOdd {
+		return "evenodd"
+	}
+	return "nonzero"
+}
+
+// Inside returns true if a point with winding number w is inside under the fill rule.
+func (fr FillRule) Inside(w int) bool {
+	if fr == EvenOdd {
+		return w%2 != 0
+	}
+	return w != 0
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (fr FillRule) MarshalText() ([]byte, error) {
+	return []byte(fr.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (fr *FillRule) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "nonzero":
+		*fr = NonZero
+	case "evenodd":
+		*fr = EvenOdd
+	default:
+		return fmt.Errorf("Not a valid fill rule %q", string(b))
+	}
+	return nil
+}
+
+// FillRule returns the fill rule of the shape.
+func (s *Shape) FillRule() FillRule {
+	return s.rule
+}
+
+// SetFillRule sets the fill rule of the shape.
+func (s *Shape) SetFillRule(rule FillRule) *Shape {
+	s.rule = rule
+	s.mask = nil
+	return s
 }
 
 // BoundingBox calculates a bounding box that the Shape is guaranteed to fit within.
@@ -47,8 +106,35 @@ func (s *Shape) Bounds() image.Rectangle {
 	return image.Rectangle{image.Point{fx, fy}, image.Point{cx, cy}}
 }
 
+// TightBoundingBox calculates the minimal bounding box of the Shape from the tight bounding boxes of
+// its paths.
+func (s *Shape) TightBoundingBox() [][]float64 {
+	if s.tbbox == nil {
+		var bb [][]float64
+		for _, path := range s.paths {
+			if bb == nil {
+				bb = path.TightBoundingBox()
+			} else {
+				bbp := path.TightBoundingBox()
+				bb = util.BoundingBox(bb[0], bb[1], bbp[0], bbp[1])
+			}
+		}
+		s.tbbox = bb
+	}
+	return s.tbbox
+}
+
+// TightBounds calculates the minimal rectangle that the shape fits within.
+func (s *Shape) TightBounds() image.Rectangle {
+	bb := s.TightBoundingBox()
+	if bb == nil {
+		return image.Rectangle{}
+	}
+	return util.BBToRect(bb)
+}
+
 // Mask returns an Alpha image defined by the shape's bounds, containing the result
-// of rendering the shape.
+// of rendering the shape with its fill rule.
 func (s *Shape) Mask() *image.Alpha {
 	if s.mask != nil {
 		return s.mask
@@ -59,7 +145,7 @@ func (s *Shape) Mask() *image.Alpha {
 	return s.mask
 }
 
-// Contains returns true if the points are contained within the shape, false otherwise.
+// Contains returns true if the points are contained within the shape's mask, false otherwise.
 func (s *Shape) Contains(pts ...[]float64) bool {
 	rect := s.Bounds()
 	mask := s.Mask()
@@ -102,6 +188,7 @@ func (s *Shape) AddPaths(paths ...*Path) {
 		}
 	}
 	s.bbox = nil
+	s.tbbox = nil
 	s.mask = nil
 }
 
@@ -124,7 +211,7 @@ func (s *Shape) Copy() *Shape {
 		paths[i] = path.Copy()
 	}
 
-	return &Shape{paths, nil, nil, s.parent}
+	return &Shape{paths, nil, nil, nil, s.parent, s.rule}
 }
 
 // Transform applies a transform to all the paths in the shape
@@ -161,7 +248,7 @@ func (s *Shape) ProcessPaths(proc PathProcessor) *Shape {
 		}
 	}
 
-	return &Shape{np, nil, nil, s}
+	return &Shape{np, nil, nil, nil, s, s.rule}
 }
 
 // String converts a shape into a string.
@@ -170,14 +257,16 @@ func (s *Shape) String() string {
 	return string(b)
 }
 
-// PointInShape returns true if the point is contained within any path within the shape.
+// PointInShape returns true if the point is contained within the shape according to the shape's
+// fill rule. The winding numbers of all the paths, which are treated as closed, are summed before
+// the rule is applied.
 func (s *Shape) PointInShape(pt []float64) bool {
+	sum := 0
 	for _, path := range s.paths {
-		if path.PointInPath(pt) {
-			return true
-		}
+		ppts, _ := path.PolyLine()
+		sum += util.WindingNumber(pt, ppts...)
 	}
-	return false
+	return s.rule.Inside(sum)
 }
 
 /*
@@ -186,11 +275,12 @@ func (s *Shape) PointInShape(pt []float64) bool {
 
 type shape struct {
 	Paths []*Path
+	Rule  FillRule `json:"FillRule,omitempty" xml:"fill-rule,attr,omitempty"`
 }
 
 // MarshalJSON implements the encoding/json.Marshaler interface
 func (s *Shape) MarshalJSON() ([]byte, error) {
-	return json.Marshal(shape{s.paths})
+	return json.Marshal(shape{s.paths, s.rule})
 }
 
 // UnmarshalJSON implements the encoding/json.Unmarshaler interface
@@ -201,9 +291,11 @@ func (s *Shape) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	s.paths = sj.Paths
+	s.rule = sj.Rule
 
 	// Reset everything else
 	s.bbox = nil
+	s.tbbox = nil
 	s.mask = nil
 	s.parent = nil
 
@@ -212,7 +304,7 @@ func (s *Shape) UnmarshalJSON(b []byte) error {
 
 // MarshalXML implements the encoding/xml.Marshaler interface
 func (s *Shape) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	return e.EncodeElement(shape{s.paths}, xml.StartElement{Name: xml.Name{"", "g"}})
+	return e.EncodeElement(shape{s.paths, s.rule}, xml.StartElement{Name: xml.Name{"", "g"}})
 }
 
 // UnmarshalXML is not supported.
@@ -257,6 +349,7 @@ func (s *Shape) UnmarshalText(b []byte) error {
 
 	// Reset everything else
 	s.bbox = nil
+	s.tbbox = nil
 	s.mask = nil
 	s.parent = nil
 
@@ -33,3 +33,21 @@ func ExampleBucketProc() {
 	fmt.Printf("See stars.png")
 	// Output: See stars.png
 }
+
+// Demonstrates placing shapes at regular distances along a path. The spacing is measured along the
+// path and, since the next shape in the sequence falls there, the open path's end gets a shape too.
+func ExampleShapesProc() {
+	path := g2d.Line([]float64{0, 0}, []float64{120, 0})
+	square := g2d.NewShape(g2d.Polygon([]float64{-1, -1}, []float64{1, -1}, []float64{1, 1}, []float64{-1, 1}))
+
+	// A nil shape leaves a gap
+	pp := g2d.NewShapesProc([]*g2d.Shape{square, nil}, 30, g2d.RotRelative)
+	for _, placed := range path.Process(pp) {
+		pt := placed.Steps()[0][0]
+		fmt.Printf("%.2f %.2f\n", pt[0], pt[1])
+	}
+	// Output:
+	// -1.00 -1.00
+	// 59.00 -1.00
+	// 119.00 -1.00
+}
@@ -0,0 +1,47 @@
+package graphics2d
+
+import "github.com/jphsd/graphics2d/util"
+
+// Polyline simplification processors. Paths containing curves are flattened first.
+
+// DouglasPeuckerProc reduces the number of points in a path using the Douglas-Peucker algorithm. Points
+// within Tolerance of the simplified path are removed. If Preserve is set, then closed paths remain at
+// least triangles and points are kept where removing them would cause the path to cross itself.
+type DouglasPeuckerProc struct {
+	Tolerance float64
+	Preserve  bool
+}
+
+// Process implements the PathProcessor interface.
+func (dp DouglasPeuckerProc) Process(p *Path) []*Path {
+	pts, closed := p.PolyLine()
+	if len(pts) < 2 {
+		return []*Path{p.Copy()}
+	}
+	return []*Path{simplifiedPath(util.DouglasPeucker(dp.Tolerance, closed, dp.Preserve, pts...), closed)}
+}
+
+// VisvalingamProc reduces the number of points in a path using the Visvalingam-Whyatt algorithm. Points
+// are removed until all the triangles formed by a point and its neighbors have an area of at least
+// Tolerance * Tolerance. If Preserve is set, then closed paths remain at least triangles and points are
+// kept where removing them would cause the path to cross itself.
+type VisvalingamProc struct {
+	Tolerance float64
+	Preserve  bool
+}
+
+// Process implements the PathProcessor interface.
+func (vp VisvalingamProc) Process(p *Path) []*Path {
+	pts, closed := p.PolyLine()
+	if len(pts) < 2 {
+		return []*Path{p.Copy()}
+	}
+	return []*Path{simplifiedPath(util.VisvalingamWhyatt(vp.Tolerance, closed, vp.Preserve, pts...), closed)}
+}
+
+func simplifiedPath(pts [][]float64, closed bool) *Path {
+	if closed {
+		return Polygon(pts...)
+	}
+	return PolyLine(pts...)
+}
@@ -0,0 +1,29 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates reducing the number of points in a flattened circle with the Douglas-Peucker and
+// Visvalingam-Whyatt path processors.
+func ExampleDouglasPeuckerProc() {
+	circle := g2d.Circle([]float64{0, 0}, 100).Flatten(0.01)
+	fmt.Printf("flattened %d\n", len(circle.Steps()))
+
+	for _, tol := range []float64{0.1, 1, 10} {
+		dp := circle.Process(g2d.DouglasPeuckerProc{tol, true})[0]
+		vw := circle.Process(g2d.VisvalingamProc{tol, true})[0]
+		fmt.Printf("%.1f: DP %d VW %d\n", tol, len(dp.Steps()), len(vw.Steps()))
+	}
+
+	// A point is left as is
+	point := g2d.NewPath([]float64{10, 10})
+	fmt.Println(point.Process(g2d.DouglasPeuckerProc{1, true})[0].Steps(), point.Process(g2d.VisvalingamProc{1, true})[0].Steps())
+	// Output:
+	// flattened 401
+	// 0.1: DP 129 VW 257
+	// 1.0: DP 33 VW 68
+	// 10.0: DP 9 VW 17
+	// [[[10 10]]] [[[10 10]]]
+}
@@ -0,0 +1,189 @@
+package graphics2d
+
+// Smoothing path processors based on subdivision. Chaikin's corner cutting converges to the uniform
+// quadratic B-spline of the path's points and the cubic B-spline subdivision scheme converges to the
+// uniform cubic B-spline. Unlike CurveProc, the resultant paths don't pass through the points.
+// Paths containing curves are flattened first.
+
+// ChaikinProc smooths a path by repeatedly replacing each line with points a quarter and three quarters
+// along it. If Fixed is set, the end points of an open path are retained. If Curves is set, then
+// Iterations is ignored and the limit curve is emitted as quadratic steps.
+type ChaikinProc struct {
+	Iterations int
+	Fixed      bool
+	Curves     bool
+}
+
+// Process implements the PathProcessor interface.
+func (cp ChaikinProc) Process(p *Path) []*Path {
+	pts, closed := p.PolyLine()
+	n := len(pts)
+	if n < 3 {
+		return []*Path{p.Copy()}
+	}
+	fixed := cp.Fixed && !closed
+
+	if cp.Curves {
+		// Quadratics joining the line mid points, with the points as control points
+		mid := func(i int) []float64 {
+			a, b := pts[i%n], pts[(i+1)%n]
+			return []float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+		}
+		var res *Path
+		if closed {
+			res = NewPath(mid(n - 1))
+			for i := range n {
+				res.AddStep(pts[i], mid(i))
+			}
+			res.Close()
+			return []*Path{res}
+		}
+		if fixed {
+			res = NewPath(pts[0])
+			res.AddStep(mid(0))
+		} else {
+			res = NewPath(mid(0))
+		}
+		for i := 1; i < n-1; i++ {
+			res.AddStep(pts[i], mid(i))
+		}
+		if fixed {
+			res.AddStep(pts[n-1])
+		}
+		return []*Path{res}
+	}
+
+	for range cp.Iterations {
+		pts = chaikin(pts, closed, fixed)
+	}
+	return []*Path{simplifiedPath(pts, closed)}
+}
+
+// chaikin performs one iteration of corner cutting.
+func chaikin(pts [][]float64, closed, fixed bool) [][]float64 {
+	n := len(pts)
+	ns := n - 1
+	if closed {
+		ns++
+	}
+	res := make([][]float64, 0, 2*ns+2)
+	if fixed {
+		res = append(res, pts[0])
+	}
+	for i := range ns {
+		a, b := pts[i], pts[(i+1)%n]
+		res = append(res,
+			[]float64{0.75*a[0] + 0.25*b[0], 0.75*a[1] + 0.25*b[1]},
+			[]float64{0.25*a[0] + 0.75*b[0], 0.25*a[1] + 0.75*b[1]})
+	}
+	if fixed {
+		res = append(res, pts[n-1])
+	}
+	return res
+}
+
+// BSplineProc smooths a path by repeatedly applying the cubic B-spline subdivision rules - a point is
+// added at the middle of each line and each existing point is moved to 3/4 of itself plus 1/8 of each
+// of its neighbors. If Fixed is set, the end points of an open path are retained by reflecting their
+// neighbors through them, so the lines converge to the same curve as is emitted when Curves is set.
+// If Curves is set, then Iterations is ignored and the limit curve is emitted as cubic steps.
+type BSplineProc struct {
+	Iterations int
+	Fixed      bool
+	Curves     bool
+}
+
+// Process implements the PathProcessor interface.
+func (bp BSplineProc) Process(p *Path) []*Path {
+	pts, closed := p.PolyLine()
+	n := len(pts)
+	if n < 3 {
+		return []*Path{p.Copy()}
+	}
+	fixed := bp.Fixed && !closed
+
+	if bp.Curves {
+		cps := pts
+		if closed {
+			// Wrap the points so every point starts a span
+			cps = append(append(append([][]float64{}, pts[n-1]), pts...), pts[0], pts[1])
+		} else if fixed {
+			cps = bsplineEnds(pts)
+		} else if n < 4 {
+			return []*Path{p.Copy()}
+		}
+		spans := bsplineSpans(cps)
+		res := NewPath(spans[0][0])
+		for _, span := range spans {
+			res.AddStep(span[1:]...)
+		}
+		if closed {
+			res.Close()
+		}
+		return []*Path{res}
+	}
+
+	for range bp.Iterations {
+		if fixed {
+			// Subdivide with the same end points as the curves and drop the points beyond the ends
+			npts := bsplineSubdivide(bsplineEnds(pts), false)
+			npts = npts[1 : len(npts)-1]
+			npts[0], npts[len(npts)-1] = pts[0], pts[n-1]
+			pts = npts
+		} else {
+			pts = bsplineSubdivide(pts, closed)
+		}
+		n = len(pts)
+	}
+	return []*Path{simplifiedPath(pts, closed)}
+}
+
+// bsplineEnds returns the points with a phantom point added at each end, the reflection of the end
+// point's neighbor through it, so that the B-spline starts and ends at the end points.
+func bsplineEnds(pts [][]float64) [][]float64 {
+	n := len(pts)
+	a, b := pts[0], pts[1]
+	c, d := pts[n-1], pts[n-2]
+	res := append([][]float64{{2*a[0] - b[0], 2*a[1] - b[1]}}, pts...)
+	return append(res, []float64{2*c[0] - d[0], 2*c[1] - d[1]})
+}
+
+// bsplineSubdivide performs one iteration of cubic B-spline subdivision.
+func bsplineSubdivide(pts [][]float64, closed bool) [][]float64 {
+	n := len(pts)
+	edge := func(i int) []float64 {
+		a, b := pts[i%n], pts[(i+1)%n]
+		return []float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+	}
+	vertex := func(i int) []float64 {
+		a, b, c := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+		return []float64{(a[0] + 6*b[0] + c[0]) / 8, (a[1] + 6*b[1] + c[1]) / 8}
+	}
+	res := make([][]float64, 0, 2*n+1)
+	if closed {
+		for i := range n {
+			res = append(res, vertex(i), edge(i))
+		}
+		return res
+	}
+	res = append(res, edge(0))
+	for i := 1; i < n-1; i++ {
+		res = append(res, vertex(i), edge(i))
+	}
+	return res
+}
+
+// bsplineSpans converts the uniform cubic B-spline control points into Bezier cubics.
+func bsplineSpans(pts [][]float64) []Part {
+	res := make([]Part, 0, len(pts)-3)
+	for i := 0; i+3 < len(pts); i++ {
+		a, b, c, d := pts[i], pts[i+1], pts[i+2], pts[i+3]
+		res = append(res, Part{
+			{(a[0] + 4*b[0] + c[0]) / 6, (a[1] + 4*b[1] + c[1]) / 6},
+			{(2*b[0] + c[0]) / 3, (2*b[1] + c[1]) / 3},
+			{(b[0] + 2*c[0]) / 3, (b[1] + 2*c[1]) / 3},
+			{(b[0] + 4*c[0] + d[0]) / 6, (b[1] + 4*c[1] + d[1]) / 6},
+		})
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"math"
+)
+
+// Demonstrates smoothing an open polyline, with its end points fixed, by Chaikin corner cutting and
+// cubic B-spline subdivision, and the exact limit curves of both.
+func ExampleChaikinProc() {
+	path := g2d.PolyLine([]float64{0, 0}, []float64{100, 0}, []float64{100, 100}, []float64{200, 50})
+
+	lines := path.Process(g2d.ChaikinProc{3, true, false})[0]
+	quads := path.Process(g2d.ChaikinProc{0, true, true})[0]
+	fmt.Printf("Chaikin %d lines, limit %v\n", len(lines.Steps())-1, quads)
+
+	lines = path.Process(g2d.BSplineProc{3, true, false})[0]
+	cubics := path.Process(g2d.BSplineProc{0, true, true})[0]
+	fmt.Printf("B-spline %d lines, limit %v\n", len(lines.Steps())-1, cubics)
+
+	// The subdivided lines converge to the limit curve
+	lines = path.Process(g2d.BSplineProc{8, true, false})[0]
+	maxd := 0.0
+	for _, step := range lines.Steps() {
+		_, _, d2 := cubics.ProjectPoint(step[0])
+		maxd = math.Max(maxd, math.Sqrt(d2))
+	}
+	fmt.Printf("8 iterations within %.4f of the limit\n", maxd)
+	// Output:
+	// Chaikin 31 lines, limit P 0.000000,0.000000 1 50.000000,0.000000 2 100.000000,0.000000 100.000000,50.000000 2 100.000000,100.000000 150.000000,75.000000 1 200.000000,50.000000
+	// B-spline 24 lines, limit P 0.000000,0.000000 3 33.333333,0.000000 66.666667,0.000000 83.333333,16.666667 3 100.000000,33.333333 100.000000,66.666667 116.666667,75.000000 3 133.333333,83.333333 166.666667,66.666667 200.000000,50.000000
+	// 8 iterations within 0.0005 of the limit
+}
@@ -8,7 +8,9 @@ import (
 
 // SnipProc contains the snip pattern and offset. The snip pattern represents lengths of state0, state1,
 // ... stateN-1, and is in the same coordinate system as the path. The offset provides the ability to
-// start from anywhere in the pattern.
+// start from anywhere in the pattern. Lengths along the path are measured with the path's curves
+// subdivided until they're within Flatten of flat, or with the path's own arc-length table if Flatten
+// isn't positive.
 type SnipProc struct {
 	N       int
 	Pattern []float64
@@ -97,60 +99,31 @@ func (sp *SnipProc) Offset(offs float64) {
 
 // Process implements the PathProcessor interface.
 func (sp *SnipProc) Process(p *Path) []*Path {
-	// Flatten the path parts
 	parts := p.Parts()
 	np := len(parts)
 	if np == 0 {
 		return []*Path{p}
 	}
-	fparts := make([][]Part, np) // part:subparts:points:xy
-	for i, part := range parts {
-		fparts[i] = FlattenPart(sp.Flatten, part)
-	}
-	lparts := getLengths(fparts)
 
-	// Use flattened parts to build list of parts and their t values where there's a state change
+	// Use an arc-length table to find the parts and their t values where there's a state change
+	at := p.arcLengths()
+	if sp.Flatten > 0 {
+		at = newArcTableFlat(parts, sp.Flatten)
+	}
 	patind := sp.patind
-	delta := sp.delta
 	chind := []int{}
 	cht := []float64{}
+	total := at.starts[np]
+	for s := sp.delta; s < total; {
+		i, t := at.partT(s)
+		chind = append(chind, i)
+		cht = append(cht, t)
 
-	for i, lpart := range lparts {
-		// last lpart contains the length of this part
-		nsegs := len(lpart) - 1
-		partlen := lpart[nsegs][0] // total length of this part
-		if partlen < delta {
-			// skip this part
-			delta -= partlen
-			continue
-		}
-		// walk individual line segs until we find the one with the state change in it
-		for j := range nsegs {
-			length := lpart[j][1]
-			if length < delta {
-				// skip this seg
-				delta -= length
-				continue
-			}
-			lsum := 0.0
-			for length > delta {
-				rem := length - delta
-				vlen := lpart[j][0] + lsum + delta
-				lsum += delta
-
-				tlen := vlen / partlen
-				chind = append(chind, i)
-				cht = append(cht, tlen)
-
-				patind++
-				if patind == len(sp.Pattern) {
-					patind = 0
-				}
-				delta = sp.Pattern[patind]
-				length = rem
-			}
-			delta -= length
+		patind++
+		if patind == len(sp.Pattern) {
+			patind = 0
 		}
+		s += sp.Pattern[patind]
 	}
 
 	npp := len(chind)
@@ -159,93 +132,6 @@ func (sp *SnipProc) Process(p *Path) []*Path {
 		return []*Path{p.Copy()}
 	}
 
-	// Build snipped paths based on part indices and t values as split points.
-	// This way we preserve the original curves.
-	cht = convTVals(chind, cht)
-	res := make([]*Path, 0, npp+1)
-	rem := parts[0]    // part remaining after last split
-	pind := 0          // current index into parts
-	pparts := []Part{} // parts collected towards next path
-
-	for i := range npp {
-		p, t := chind[i], cht[i]
-		for p > pind {
-			pparts = append(pparts, rem)
-			pind++
-			rem = parts[pind]
-		}
-		// rem is the correct part
-		if util.Equals(t, 0) {
-			// t == 0
-			if len(pparts) == 0 {
-				continue
-			}
-			lp := PartsToPath(pparts...)
-			res = append(res, lp)
-			// rem already set
-		} else {
-			// state change is in this part, split it at t
-			pieces := util.SplitCurve(rem, t)
-			pparts = append(pparts, pieces[0])
-			lp := PartsToPath(pparts...)
-			res = append(res, lp)
-			rem = pieces[1]
-		}
-		pparts = []Part{}
-	}
-
-	// Handle remaining path
-	pparts = append(pparts, rem)
-	pind++
-	for pind < np {
-		pparts = append(pparts, parts[pind])
-		pind++
-	}
-	lp := PartsToPath(pparts...)
-	return append(res, lp)
-}
-
-// part:lineseg:cumpartlen/len
-func getLengths(parts [][]Part) [][][]float64 {
-	res := make([][][]float64, len(parts))
-	for i, part := range parts {
-		sumPart := 0.0
-		n := len(part) + 1
-		res[i] = make([][]float64, n)
-		for j, lineseg := range part {
-			dx, dy := lineseg[1][0]-lineseg[0][0], lineseg[1][1]-lineseg[0][1]
-			len := math.Hypot(dx, dy)
-			res[i][j] = []float64{sumPart, len}
-			sumPart += len
-		}
-		// Last chunk in part is the part total
-		res[i][n-1] = []float64{sumPart}
-	}
-	return res
-}
-
-// t vals need to be relative to the remaining part after splitting.
-// e.g. part split at 0.25, 0.5 and 0.666 => 0.25, 0.333 and .333
-func convTVals(chind []int, cht []float64) []float64 {
-	n := len(chind)
-	res := make([]float64, n)
-	p := -1
-	lt := 0.0
-	ll := 1.0
-	for i := range n {
-		if chind[i] != p {
-			// Reset
-			lt = cht[i]
-			ll = 1 - lt
-			res[i] = lt
-			p = chind[i]
-			continue
-		}
-		t := cht[i]
-		d := t - lt
-		res[i] = d / ll
-		lt = t
-		ll -= d
-	}
-	return res
+	// Split the path at the part indices and t values. This way we preserve the original curves.
+	return p.splitAtPartTs(chind, cht)
 }
@@ -0,0 +1,131 @@
+package graphics2d
+
+import (
+	"sort"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Splitting paths at t values or lengths. The parts containing a split are divided at the exact t
+// value within the part so each piece retains the order of the original step.
+// As with the arc-length methods, t for the whole path is in [0, 1] and is shared equally between
+// the parts returned by Parts().
+
+// SplitAt splits the path at the supplied t values and returns the resultant paths, in order. Values
+// outside of (0, 1) and repeated values are ignored. Note that the t returned by ProjectPoint is for the simplified path,
+// so use p.Simplify().SplitAt(t) in that case.
+func (p *Path) SplitAt(ts ...float64) []*Path {
+	n := len(p.Parts())
+	inds, pts := make([]int, len(ts)), make([]float64, len(ts))
+	for i, t := range ts {
+		inds[i], pts[i] = partIndexT(t, n)
+	}
+	return p.splitAtPartTs(inds, pts)
+}
+
+// SplitAtLengths splits the path at the supplied distances along it and returns the resultant paths,
+// in order. Distances outside of (0, ArcLength()) and repeated distances are ignored.
+func (p *Path) SplitAtLengths(ls ...float64) []*Path {
+	inds, pts := make([]int, len(ls)), make([]float64, len(ls))
+	for i, l := range ls {
+		inds[i], pts[i] = p.PartAtLength(l)
+	}
+	return p.splitAtPartTs(inds, pts)
+}
+
+// SubPath returns the section of the path between from and to. If from is greater than to and the
+// path is closed, then the section wraps around the path's start.
+func (p *Path) SubPath(from, to float64) *Path {
+	from, to = clamp01(from), clamp01(to)
+	if from == to {
+		parts := p.Parts()
+		i, t := partIndexT(from, len(parts))
+		pt := util.DeCasteljau(parts[i], t)
+		return NewPath([]float64{pt[0], pt[1]})
+	}
+	if from > to {
+		if !p.closed {
+			from, to = to, from
+		} else if from == 1 {
+			from = 0
+		} else {
+			// Join the end of the path to its start
+			paths := p.SplitAt(to, from)
+			parts := paths[len(paths)-1].Parts()
+			if to > 0 {
+				parts = append(parts, paths[0].Parts()...)
+			}
+			res := PartsToPath(parts...)
+			res.parent = p
+			return res
+		}
+	}
+	paths := p.SplitAt(from, to)
+	if from > 0 {
+		return paths[1]
+	}
+	return paths[0]
+}
+
+// splitAtPartTs splits the path at the parts and t values within them.
+func (p *Path) splitAtPartTs(inds []int, ts []float64) []*Path {
+	parts := p.Parts()
+	n := len(parts)
+	if n == 0 || len(parts[0]) == 1 {
+		return []*Path{p.Copy()}
+	}
+
+	// Convert splits at the end of a part to the start of the next, and sort them
+	type split struct {
+		i int
+		t float64
+	}
+	splits := make([]split, 0, len(inds))
+	for k, i := range inds {
+		t := clamp01(ts[k])
+		if t == 1 {
+			i, t = i+1, 0
+		}
+		if (i == 0 && t == 0) || i >= n {
+			continue
+		}
+		splits = append(splits, split{i, t})
+	}
+	sort.Slice(splits, func(a, b int) bool {
+		return splits[a].i < splits[b].i || splits[a].i == splits[b].i && splits[a].t < splits[b].t
+	})
+
+	res := []*Path{}
+	cur := []Part{} // parts collected towards the next path
+	pind, lt := 0, 0.0
+	for _, s := range splits {
+		if s.i == pind && s.t == lt {
+			// Duplicate
+			continue
+		}
+		// Collect the remainder of the current part and any whole parts up to the split
+		for pind < s.i {
+			cur = append(cur, subPart(parts[pind], lt, 1))
+			pind++
+			lt = 0
+		}
+		if s.t > 0 {
+			cur = append(cur, subPart(parts[pind], lt, s.t))
+			lt = s.t
+		}
+		res = append(res, PartsToPath(cur...))
+		cur = []Part{}
+	}
+
+	// Handle the remaining path
+	for pind < n {
+		cur = append(cur, subPart(parts[pind], lt, 1))
+		pind++
+		lt = 0
+	}
+	res = append(res, PartsToPath(cur...))
+	for _, path := range res {
+		path.parent = p
+	}
+	return res
+}
@@ -0,0 +1,32 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+)
+
+// Demonstrates splitting a path at t values and lengths, and extracting a section of a closed path
+// that wraps around its start.
+func ExamplePath_SplitAt() {
+	path := g2d.NewPath([]float64{0, 0})
+	path.AddStep([]float64{100, 0})
+	path.AddStep([]float64{100, 100}, []float64{200, 100})
+
+	for _, p := range path.SplitAt(0.25, 0.75) {
+		fmt.Println(p)
+	}
+	for _, p := range path.SplitAtLengths(50, 150) {
+		fmt.Printf("%.2f\n", p.ArcLength())
+	}
+
+	square := g2d.Polygon([]float64{0, 0}, []float64{100, 0}, []float64{100, 100}, []float64{0, 100})
+	fmt.Println(square.SubPath(0.875, 0.125))
+	// Output:
+	// P 0.000000,0.000000 1 50.000000,0.000000
+	// P 50.000000,0.000000 1 100.000000,0.000000 2 100.000000,50.000000 125.000000,75.000000
+	// P 125.000000,75.000000 2 150.000000,100.000000 200.000000,100.000000
+	// 50.00
+	// 100.00
+	// 112.32
+	// P 0.000000,50.000000 1 0.000000,0.000000 1 50.000000,0.000000
+}
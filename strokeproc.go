@@ -88,7 +88,10 @@ func NewStrokeProc(w float64) *StrokeProc {
 
 // NewStrokeProcExt creates a trace stroke path processor where the widths are specified
 // separately for each side of the stroke. This allows the stroke to be offset to the left or right
-// of the path being processed.
+// of the path being processed. The sides are traced with TraceProc, flattened to within d and joined
+// with the bevel join. If d is negative, then the sides are traced with AccurateTraceProc instead, so
+// curves are offset to within -d of their true offsets, and are joined with jf, or the bevel join if
+// it's nil.
 func NewStrokeProcExt(rw, lw float64,
 	jf func(Part, []float64, Part) []Part,
 	d float64,
@@ -99,9 +102,18 @@ func NewStrokeProcExt(rw, lw float64,
 	if lw > 0 {
 		lw = -lw
 	}
+	var rhs, lhs PathProcessor
+	if d < 0 {
+		if jf == nil {
+			jf = JoinBevel
+		}
+		rhs, lhs = AccurateTraceProc{rw / 2, -d, jf}, AccurateTraceProc{lw / 2, -d, jf}
+	} else {
+		rhs, lhs = TraceProc{rw / 2, d, JoinBevel}, TraceProc{lw / 2, d, JoinBevel}
+	}
 	return &StrokeProc{
-		RHSProc:      TraceProc{rw / 2, d, JoinBevel},
-		LHSProc:      TraceProc{lw / 2, d, JoinBevel},
+		RHSProc:      rhs,
+		LHSProc:      lhs,
 		PostSideProc: nil,
 		Width:        rw - lw,
 		PointFunc:    PointCircle,
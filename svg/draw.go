@@ -8,18 +8,19 @@ import (
 )
 
 type xshape struct {
-	Color string `xml:"fill,attr"`
+	Color string       `xml:"fill,attr"`
+	Rule  g2d.FillRule `xml:"fill-rule,attr,omitempty"`
 	Paths []*g2d.Path
 }
 
-// RenderColoredShape writes SVG describing the shape and its color to the encoder.
+// RenderColoredShape writes SVG describing the shape, its fill rule and its color to the encoder.
 func RenderColoredShape(enc *xml.Encoder, shape *g2d.Shape, col color.Color) error {
 	r, g, b, _ := col.RGBA() // [0, 0xffff]
 	r >>= 8
 	g >>= 8
 	b >>= 8
 	cstr := fmt.Sprintf("#%02x%02x%02x", r, g, b)
-	return enc.EncodeElement(xshape{cstr, shape.Paths()}, xml.StartElement{Name: xml.Name{"", "g"}})
+	return enc.EncodeElement(xshape{cstr, shape.FillRule(), shape.Paths()}, xml.StartElement{Name: xml.Name{"", "g"}})
 }
 
 // DrawShape writes SVG describing the shape as rendered by the pen to the encoder.
@@ -28,7 +29,7 @@ func DrawShape(enc *xml.Encoder, shape *g2d.Shape, pen *g2d.Pen) error {
 		shape = shape.Transform(pen.Xfm)
 	}
 	if pen.Stroke != nil {
-		shape = shape.ProcessPaths(pen.Stroke)
+		shape = shape.ProcessPaths(pen.Stroke).SetFillRule(g2d.NonZero)
 	}
 	col := pen.Filler.At(0, 0) // Assumes the filler image has constant color
 	return RenderColoredShape(enc, shape, col)
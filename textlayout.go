@@ -0,0 +1,293 @@
+package graphics2d
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Multi-line text layout. Each paragraph, separated by newlines, is laid out with kerning as for
+// StringToShape, and then broken into lines greedily at white space so that each line fits within
+// the box width. A word wider than the box is left on a line by itself. The lines are stacked
+// downwards from the top of the box, the first baseline being the font's ascent below it, and
+// spaced by the font's line height (ascent + descent + line gap) times the line spacing.
+
+// TextLayout contains the font and layout options for text. Size is the size of the font's em in
+// layout units, the font's units per em if 0. Width is the width of the box the text is wrapped to,
+// or 0 for no wrapping. LineSpacing multiplies the font's line height, 1 if 0, and Tracking is the
+// additional space, in layout units, added after each glyph. If Fonts is set, then each rune is taken
+// from the first font in the set that has it, and Font is ignored; the line metrics come from the
+// set's first font.
+type TextLayout struct {
+	Font        *sfnt.Font
+	Size        float64
+	Width       float64
+	Align       TextAlign
+	LineSpacing float64
+	Tracking    float64
+	Fonts       *FontSet
+}
+
+// TextLine describes a line of laid out text, its baseline and the horizontal extent of its glyphs'
+// advances.
+type TextLine struct {
+	Text       string
+	Glyphs     []*Shape
+	Baseline   float64
+	Start, End float64
+}
+
+// TextBlock contains the result of a text layout.
+type TextBlock struct {
+	Glyphs []*Shape
+	Lines  []*TextLine
+	bbox   [][]float64
+	// Decoration positions, as offsets from the baseline, and thickness
+	upos, spos, thick float64
+}
+
+// NewTextLayout creates a new text layout for the font with the given size and box width, and the
+// default alignment and spacing.
+func NewTextLayout(tfont *sfnt.Font, size, width float64) *TextLayout {
+	return &TextLayout{tfont, size, width, AlignStart, 1, 0, nil}
+}
+
+// Layout lays out the string and returns the positioned glyph shapes, grouped in lines. Glyphs with
+// no paths are not returned (e.g. space etc.). With AlignJustify, the last line of each paragraph is
+// aligned to the start.
+func (tl *TextLayout) Layout(str string) (*TextBlock, error) {
+	fs := tl.Fonts
+	if fs == nil {
+		fs = NewFontSet(tl.Font)
+	}
+	if len(fs.Fonts) == 0 {
+		return nil, fmt.Errorf("font set has no fonts")
+	}
+	tfont := fs.Fonts[0]
+	upem := fs.UnitsPerEm()
+	scale := 1.0
+	if tl.Size > 0 {
+		scale = tl.Size / upem
+	}
+	spacing := tl.LineSpacing
+	if spacing <= 0 {
+		spacing = 1
+	}
+	var buffer sfnt.Buffer
+	metrics, err := tfont.Metrics(&buffer, fixed.I(int(upem)), font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	ascent, descent := I266ToF64(metrics.Ascent)*scale, I266ToF64(metrics.Descent)*scale
+	height := I266ToF64(metrics.Height) * scale * spacing
+
+	res := &TextBlock{}
+	res.upos, res.spos, res.thick = decorationMetrics(tfont, metrics)
+	res.upos *= scale
+	res.spos *= scale
+	res.thick *= scale
+
+	// Break the paragraphs into lines
+	lines := []textLine{}
+	for _, para := range strings.Split(str, "\n") {
+		glyphs, _, err := fs.layoutString(para)
+		if err != nil {
+			return nil, err
+		}
+		words := textWords(glyphs)
+		if len(words) == 0 {
+			lines = append(lines, textLine{last: true})
+			continue
+		}
+		s := 0
+		for i, w := range words {
+			if i > s && tl.Width > 0 && tl.width(glyphs, words[s][0], w[1], scale) > tl.Width {
+				lines = append(lines, newTextLine(glyphs, words[s:i], false))
+				s = i
+			}
+		}
+		lines = append(lines, newTextLine(glyphs, words[s:], true))
+	}
+
+	// Position the glyphs in each line
+	boxw := tl.Width
+	if boxw <= 0 {
+		for _, l := range lines {
+			if len(l.words) > 0 {
+				boxw = max(boxw, tl.width(l.glyphs, 0, len(l.glyphs), scale))
+			}
+		}
+	}
+	baseline := ascent
+	for i, l := range lines {
+		if i > 0 {
+			baseline += height
+		}
+		tline := &TextLine{Baseline: baseline}
+		res.Lines = append(res.Lines, tline)
+		if len(l.words) == 0 {
+			continue
+		}
+		lw := tl.width(l.glyphs, 0, len(l.glyphs), scale)
+		x, gap := 0.0, 0.0
+		switch tl.Align {
+		case AlignMiddle:
+			x = (boxw - lw) / 2
+		case AlignEnd:
+			x = boxw - lw
+		case AlignJustify:
+			if !l.last && len(l.words) > 1 && lw < boxw {
+				gap = (boxw - lw) / float64(len(l.words)-1)
+			}
+		}
+		tline.Start = x
+		tline.End = x + lw
+		if gap > 0 {
+			tline.End = x + boxw
+		}
+
+		var sb strings.Builder
+		x0 := l.glyphs[0].x
+		wi := 0
+		for j, g := range l.glyphs {
+			sb.WriteRune(g.r)
+			for wi < len(l.words)-1 && j >= l.words[wi+1][0] {
+				wi++
+			}
+			if len(g.shape.Paths()) == 0 {
+				continue
+			}
+			gx := x + (g.x-x0)*scale + float64(j)*tl.Tracking + float64(wi)*gap
+			xfm := Translate(gx, baseline)
+			xfm.Scale(scale, scale)
+			s := g.shape.Transform(xfm)
+			tline.Glyphs = append(tline.Glyphs, s)
+			res.Glyphs = append(res.Glyphs, s)
+		}
+		tline.Text = sb.String()
+	}
+
+	// Measure the lines
+	minx, maxx := 0.0, 0.0
+	first := true
+	for _, l := range res.Lines {
+		if l.Start == l.End {
+			continue
+		}
+		if first {
+			minx, maxx, first = l.Start, l.End, false
+			continue
+		}
+		minx, maxx = min(minx, l.Start), max(maxx, l.End)
+	}
+	res.bbox = [][]float64{{minx, 0}, {maxx, baseline + descent}}
+	return res, nil
+}
+
+// width returns the width of the glyphs from s to e (exclusive), including tracking between them.
+func (tl *TextLayout) width(glyphs []layoutGlyph, s, e int, scale float64) float64 {
+	if e <= s {
+		return 0
+	}
+	last := glyphs[e-1]
+	return (last.x+last.adv-glyphs[s].x)*scale + float64(e-s-1)*tl.Tracking
+}
+
+// textLine is a line of glyphs from its first word to its last, before positioning.
+type textLine struct {
+	glyphs []layoutGlyph
+	words  [][]int // Start and end (exclusive) of each word in glyphs
+	last   bool    // Last line of a paragraph
+}
+
+// newTextLine returns the line containing the words, which are indices into glyphs.
+func newTextLine(glyphs []layoutGlyph, words [][]int, last bool) textLine {
+	s := words[0][0]
+	nwords := make([][]int, len(words))
+	for i, w := range words {
+		nwords[i] = []int{w[0] - s, w[1] - s}
+	}
+	return textLine{glyphs[s:words[len(words)-1][1]], nwords, last}
+}
+
+// textWords returns the start and end (exclusive) of each run of non-space glyphs.
+func textWords(glyphs []layoutGlyph) [][]int {
+	res := [][]int{}
+	s := -1
+	for i, g := range glyphs {
+		if unicode.IsSpace(g.r) {
+			if s >= 0 {
+				res = append(res, []int{s, i})
+				s = -1
+			}
+		} else if s < 0 {
+			s = i
+		}
+	}
+	if s >= 0 {
+		res = append(res, []int{s, len(glyphs)})
+	}
+	return res
+}
+
+// decorationMetrics returns the underline and strikethrough positions, as offsets of their tops
+// below the baseline, and their thickness, in font units. The underline comes from the font's post
+// table or, if the font doesn't have one, is placed half way down the descent. The strikethrough is
+// centered on half the x-height, as sfnt doesn't provide the OS/2 strikeout metrics.
+func decorationMetrics(tfont *sfnt.Font, m font.Metrics) (float64, float64, float64) {
+	upem := float64(tfont.UnitsPerEm())
+	upos, thick := I266ToF64(m.Descent)/2, upem/20
+	if post := tfont.PostTable(); post != nil && post.UnderlineThickness > 0 {
+		upos, thick = -float64(post.UnderlinePosition), float64(post.UnderlineThickness)
+	}
+	xh := I266ToF64(m.XHeight)
+	if xh <= 0 {
+		xh = I266ToF64(m.Ascent) / 2
+	}
+	return upos, -(xh + thick) / 2, thick
+}
+
+// BoundingBox returns the extent of the laid out lines, from the top of the box to the descent of the
+// last line, and across the advances of the glyphs in the lines. This is the box used for aligning
+// and spacing the text rather than the extent of the glyphs' outlines.
+func (tb *TextBlock) BoundingBox() [][]float64 {
+	return tb.bbox
+}
+
+// Shape returns all the glyph shapes combined in a single shape.
+func (tb *TextBlock) Shape() *Shape {
+	res := &Shape{}
+	for _, g := range tb.Glyphs {
+		res.AddShapes(g)
+	}
+	return res
+}
+
+// Underline returns the underlines of the lines as a shape.
+func (tb *TextBlock) Underline() *Shape {
+	return tb.decoration(tb.upos)
+}
+
+// Strikethrough returns the strikethroughs of the lines as a shape.
+func (tb *TextBlock) Strikethrough() *Shape {
+	return tb.decoration(tb.spos)
+}
+
+// decoration returns a rectangle for each non-empty line with its top at offset below the baseline.
+func (tb *TextBlock) decoration(offs float64) *Shape {
+	res := &Shape{}
+	for _, l := range tb.Lines {
+		if l.Start == l.End {
+			continue
+		}
+		y := l.Baseline + offs
+		res.AddPaths(Polygon(
+			[]float64{l.Start, y}, []float64{l.End, y},
+			[]float64{l.End, y + tb.thick}, []float64{l.Start, y + tb.thick}))
+	}
+	return res
+}
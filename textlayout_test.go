@@ -0,0 +1,34 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Demonstrates wrapping and justifying a paragraph within a box 220 wide, and underlining it.
+func ExampleTextLayout() {
+	ttf, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	layout := g2d.NewTextLayout(ttf, 18, 220)
+	layout.Align = g2d.AlignJustify
+	block, err := layout.Layout("The quick brown fox jumps over the lazy dog. Pack my box with five dozen liquor jugs.")
+	if err != nil {
+		panic(err)
+	}
+	for _, line := range block.Lines {
+		fmt.Printf("%-28q %5.1f to %5.1f\n", line.Text, line.Start, line.End)
+	}
+	bb := block.BoundingBox()
+	fmt.Printf("box %.1f x %.1f, %d underlines\n", bb[1][0]-bb[0][0], bb[1][1]-bb[0][1], len(block.Underline().Paths()))
+	// Output:
+	// "The quick brown fox jumps"    0.0 to 220.0
+	// "over the lazy dog. Pack my"   0.0 to 220.0
+	// "box with five dozen liquor"   0.0 to 220.0
+	// "jugs."                        0.0 to  39.3
+	// box 220.0 x 83.2, 4 underlines
+}
@@ -0,0 +1,169 @@
+package graphics2d
+
+import (
+	"math"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// Text on a path. As in SVG's textPath, glyphs are positioned by arc length along the path, with the
+// midpoint of each glyph's advance on the path and the glyph rotated to the path's tangent there.
+// Glyphs whose midpoints fall beyond the ends of an open path are dropped, while on a closed path
+// they wrap around. When warped, every point of a glyph's flattened outline is mapped individually,
+// its x becoming the distance along the path and its y the distance along the path's normal, so the
+// glyphs bend with the path.
+
+// TextAlign specifies how text is aligned relative to its starting position.
+type TextAlign int
+
+const (
+	// AlignStart places the start of the text at the starting position
+	AlignStart TextAlign = iota
+	// AlignMiddle centers the text on the starting position
+	AlignMiddle
+	// AlignEnd places the end of the text at the starting position
+	AlignEnd
+	// AlignJustify spreads the words of a line across the width of its box (TextLayout only)
+	AlignJustify
+)
+
+// TextOnPathOptions contains the layout options for TextOnPath. Size is the size of the font's em in
+// path units, the font's units per em if 0. StartOffset is the distance along the path of the
+// starting position, LetterSpacing is the additional space, in path units, added after each glyph,
+// and Warp bends the glyphs to follow the path. Warped glyphs are flattened to within Flatten, or
+// RenderFlatten if 0.
+type TextOnPathOptions struct {
+	Size          float64
+	StartOffset   float64
+	Align         TextAlign
+	LetterSpacing float64
+	Warp          bool
+	Flatten       float64
+}
+
+// TextOnPath returns the string laid out along the path as both a single shape and as individual
+// glyph shapes. Glyphs with no paths are not returned (e.g. space etc.). If opts is nil, then the
+// defaults are used.
+func TextOnPath(tfont *sfnt.Font, str string, path *Path, opts *TextOnPathOptions) (*Shape, []*Shape, error) {
+	return NewFontSet(tfont).TextOnPath(str, path, opts)
+}
+
+// TextOnPath returns the string laid out along the path, as for TextOnPath, with each rune taken
+// from the first font in the set that has it. Size is the size of the set's em.
+func (fs *FontSet) TextOnPath(str string, path *Path, opts *TextOnPathOptions) (*Shape, []*Shape, error) {
+	if opts == nil {
+		opts = &TextOnPathOptions{}
+	}
+	glyphs, _, err := fs.layoutString(str)
+	if err != nil {
+		return nil, nil, err
+	}
+	scale := 1.0
+	if opts.Size > 0 {
+		scale = opts.Size / fs.UnitsPerEm()
+	}
+	flat := opts.Flatten
+	if flat <= 0 {
+		flat = RenderFlatten
+	}
+
+	// Find the glyph offsets, in path units, and the text's total advance
+	n := len(glyphs)
+	xs := make([]float64, n)
+	width := 0.0
+	for i, g := range glyphs {
+		xs[i] = g.x*scale + float64(i)*opts.LetterSpacing
+		width = xs[i] + g.adv*scale
+	}
+	start := opts.StartOffset
+	switch opts.Align {
+	case AlignMiddle:
+		start -= width / 2
+	case AlignEnd:
+		start -= width
+	}
+
+	l := path.ArcLength()
+	closed := path.Closed()
+	shape := &Shape{}
+	shapes := []*Shape{}
+	for i, g := range glyphs {
+		if len(g.shape.Paths()) == 0 {
+			continue
+		}
+		hadv := g.adv * scale / 2
+		mid := start + xs[i] + hadv
+		if closed {
+			mid = math.Mod(mid, l)
+			if mid < 0 {
+				mid += l
+			}
+		} else if mid < 0 || mid > l {
+			continue
+		}
+
+		var s *Shape
+		if opts.Warp {
+			s = g.shape.ProcessPaths(warpProc{path, l, closed, mid - hadv, scale, flat / scale})
+		} else {
+			pt := path.PointAtLength(mid)
+			tang := path.TangentAtLength(mid)
+			xfm := CreateAffineTransform(pt[0], pt[1], 1, math.Atan2(tang[1], tang[0]))
+			xfm.Translate(-hadv, 0)
+			xfm.Scale(scale, scale)
+			s = g.shape.Transform(xfm)
+		}
+		shapes = append(shapes, s)
+		shape.AddShapes(s)
+	}
+	return shape, shapes, nil
+}
+
+// warpProc maps the flattened points of a glyph's outlines, in font units, onto the path with the
+// glyph's origin at distance s along it.
+type warpProc struct {
+	path    *Path
+	l       float64
+	closed  bool
+	s       float64
+	scale   float64
+	flatten float64
+}
+
+// Process implements the PathProcessor interface.
+func (wp warpProc) Process(p *Path) []*Path {
+	// Flatten a copy, since the glyph's path may be shared via the glyph cache
+	pts := flatPolygon(p.Copy(), wp.flatten)
+	if len(pts) == 0 {
+		return []*Path{}
+	}
+	npts := make([][]float64, len(pts))
+	for i, pt := range pts {
+		npts[i] = wp.mapPoint(wp.s+pt[0]*wp.scale, pt[1]*wp.scale)
+	}
+	np := NewPath(npts[0])
+	for _, pt := range npts[1:] {
+		np.AddStep(pt)
+	}
+	np.Close()
+	return []*Path{np}
+}
+
+// mapPoint returns the point at distance s along the path and d along its normal. Beyond the ends
+// of an open path, the path is extended along its end tangents.
+func (wp warpProc) mapPoint(s, d float64) []float64 {
+	ext := 0.0
+	if wp.closed {
+		s = math.Mod(s, wp.l)
+		if s < 0 {
+			s += wp.l
+		}
+	} else if s < 0 {
+		ext, s = s, 0
+	} else if s > wp.l {
+		ext, s = s-wp.l, wp.l
+	}
+	pt := wp.path.PointAtLength(s)
+	tang := wp.path.TangentAtLength(s)
+	return []float64{pt[0] + ext*tang[0] - d*tang[1], pt[1] + ext*tang[1] + d*tang[0]}
+}
@@ -0,0 +1,37 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Demonstrates laying text out along a line, centered on its midpoint, and warped around a circle.
+func ExampleTextOnPath() {
+	ttf, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+
+	line := g2d.Line([]float64{0, 100}, []float64{400, 100})
+	opts := &g2d.TextOnPathOptions{Size: 40, StartOffset: 200, Align: g2d.AlignMiddle}
+	shape, glyphs, err := g2d.TextOnPath(ttf, "Hello, World", line, opts)
+	if err != nil {
+		panic(err)
+	}
+	bb := shape.BoundingBox()
+	fmt.Printf("%d glyphs from x %.1f to %.1f\n", len(glyphs), bb[0][0], bb[1][0])
+
+	circle := g2d.Circle([]float64{200, 200}, 100)
+	opts = &g2d.TextOnPathOptions{Size: 20, LetterSpacing: 2, Warp: true}
+	shape, glyphs, err = g2d.TextOnPath(ttf, "Around and around", circle, opts)
+	if err != nil {
+		panic(err)
+	}
+	bb = shape.BoundingBox()
+	fmt.Printf("%d glyphs within %.0f of the center\n", len(glyphs), max(bb[1][0]-200, 200-bb[0][0], bb[1][1]-200, 200-bb[0][1]))
+	// Output:
+	// 11 glyphs from x 90.8 to 309.4
+	// 15 glyphs within 114 of the center
+}
@@ -1,7 +1,17 @@
 package graphics2d
 
+import (
+	"math"
+
+	"github.com/jphsd/graphics2d/util"
+)
+
 // Constant width path tracer. Traces a path at a normal distance of width from the path.
 // Join types - round, bevel [default], miter
+// The offset of a curve is approximated by transforming it so its end points lie on the offset.
+// AccurateTraceProc instead offsets each curve by cubics with the exact end points and end
+// derivatives of the true offset (a Hermite approximation), subdividing the curve until the cubics
+// are within a tolerance of it.
 
 // TraceProc defines the width and join types of the trace. The gap between two adjacent
 // steps must be greater than MinGap for the join function to be called.
@@ -18,12 +28,41 @@ func NewTraceProc(w float64) TraceProc {
 
 // Process implements the PathProcessor interface.
 func (tp TraceProc) Process(p *Path) []*Path {
-	path := PartsToPath(tp.ProcessParts(p)...)
+	return tracePath(p, tp.Width, tp.ProcessParts(p))
+}
+
+// ProcessParts returns the processed path as a slice of parts, rather a path so other path
+// processors don't have to round trip path -> parts -> path -> parts (e.g. StrokeProc).
+func (tp TraceProc) ProcessParts(p *Path) []Part {
+	return traceParts(p, tp.Width, 0, tp.JoinFunc, flatKnot(tp.Flatten))
+}
+
+// AccurateTraceProc defines the width, tolerance and join types of a trace whose curves are offset
+// to within Tolerance of their true offsets.
+type AccurateTraceProc struct {
+	Width     float64
+	Tolerance float64
+	JoinFunc  func(Part, []float64, Part) []Part
+}
+
+// Process implements the PathProcessor interface.
+func (tp AccurateTraceProc) Process(p *Path) []*Path {
+	return tracePath(p, tp.Width, tp.ProcessParts(p))
+}
+
+// ProcessParts returns the processed path as a slice of parts, as for TraceProc.
+func (tp AccurateTraceProc) ProcessParts(p *Path) []Part {
+	return traceParts(p, tp.Width, tp.Tolerance, tp.JoinFunc, trimKnot)
+}
+
+// tracePath returns the trace parts of p as a path, reversed if w is negative.
+func tracePath(p *Path, w float64, parts []Part) []*Path {
+	path := PartsToPath(parts...)
 	if path == nil {
 		return []*Path{}
 	}
 
-	if tp.Width < 0 {
+	if w < 0 {
 		path = path.Reverse()
 	}
 
@@ -33,15 +72,15 @@ func (tp TraceProc) Process(p *Path) []*Path {
 	return []*Path{path}
 }
 
-// ProcessParts returns the processed path as a slice of parts, rather a path so other path
-// processors don't have to round trip path -> parts -> path -> parts (e.g. StrokeProc).
-func (tp TraceProc) ProcessParts(p *Path) []Part {
+// traceParts returns the parts of the trace of p at w, with curves offset to within tol if it's
+// greater than 0 and approximated otherwise. Knots between the offset parts are removed with kf.
+func traceParts(p *Path, w, tol float64, jf func(Part, []float64, Part) []Part,
+	kf func(Part, Part) (Part, Part, bool)) []Part {
 	// A point isn't traceable.
 	if len(p.Steps()) == 1 {
 		return []Part{}
 	}
 
-	w := tp.Width
 	if w < 0 {
 		w = -w
 		p = p.Reverse()
@@ -50,8 +89,104 @@ func (tp TraceProc) ProcessParts(p *Path) []Part {
 	// Preprocess curves into safe forms.
 	p = p.Simplify()
 	parts := p.Parts()
+	var rhs []Part
+	if tol > 0 {
+		parts, rhs = accurateOffsetParts(parts, w, tol)
+	} else {
+		rhs = offsetParts(parts, p.Tangents(), w)
+	}
+	return joinOffsets(parts, rhs, p.Closed(), jf, kf)
+}
+
+// joinOffsets connects the consecutive offset parts, trimming them with the knot function where they
+// cross and otherwise using the join function, centered on the start of the corresponding original part.
+func joinOffsets(parts, rhs []Part, closed bool, jf func(Part, []float64, Part) []Part,
+	kf func(Part, Part) (Part, Part, bool)) []Part {
+	n := len(parts)
+	nrhs := make([]Part, 0, 2*n)
+	nrhs = append(nrhs, rhs[0])
+	for i := 1; i < n; i++ {
+		last := nrhs[len(nrhs)-1]
+		// Check for knot first
+		if np, nr, ok := kf(last, rhs[i]); ok {
+			// Trim the end of nrhs[$] and start of rhs[i] at the knot
+			nrhs[len(nrhs)-1] = np
+			rhs[i] = nr
+		} else {
+			nrhs = append(nrhs, jf(last, parts[i][0], rhs[i])...)
+		}
+		nrhs = append(nrhs, rhs[i])
+	}
+
+	if closed {
+		// Join the end points
+		last := nrhs[len(nrhs)-1]
+		if np, nr, ok := kf(last, nrhs[0]); ok {
+			nrhs[len(nrhs)-1] = np
+			nrhs[0] = nr
+		} else {
+			nrhs = append(nrhs, jf(last, parts[0][0], nrhs[0])...)
+		}
+	}
+
+	return nrhs
+}
+
+// flatKnot returns a knot function that finds where p1 and p2 intersect, with the parts flattened to
+// within d, and moves the end of p1 and the start of p2 to that point.
+func flatKnot(d float64) func(Part, Part) (Part, Part, bool) {
+	return func(p1, p2 Part) (Part, Part, bool) {
+		npt := PartsIntersection(p1, p2, d)
+		if npt == nil {
+			return nil, nil, false
+		}
+		// Not strictly correct - should really figure out the t value for
+		// the point and then split part at t value to preserve the part's cp.
+		p1[len(p1)-1] = npt
+		p2[0] = npt
+		return p1, p2, true
+	}
+}
+
+// trimKnot finds the point where p1 and p2 intersect that's closest to the end of p1 and the start of
+// p2, and splits them there, returning the start of p1 and the end of p2. Taking the closest crossing
+// removes only the loop formed at the join when the parts cross more than once. The split is performed
+// at the exact t values for the point so the curves are preserved.
+func trimKnot(p1, p2 Part) (Part, Part, bool) {
+	if util.EqualsP(p1[len(p1)-1], p2[0]) {
+		// Smooth continuation, nothing to trim
+		return p1, p2, true
+	}
+	tps := partIntersections(p1, p2)
+	if len(tps) == 0 {
+		return nil, nil, false
+	}
+	best := tps[0]
+	for _, tp := range tps[1:] {
+		if 1-tp[0]+tp[1] < 1-best[0]+best[1] {
+			best = tp
+		}
+	}
+	t1, t2 := best[0], best[1]
+	pt := util.DeCasteljau(p1, t1)
+	pt = []float64{pt[0], pt[1]}
+	np1, np2 := Part{pt, pt}, Part{pt, pt}
+	if t1 > 0 {
+		np1 = subPart(p1, 0, t1)
+	}
+	if t2 < 1 {
+		np2 = subPart(p2, t2, 1)
+	}
+	// Make the ends coincident
+	np1[len(np1)-1] = pt
+	np2[0] = pt
+	return np1, np2, true
+}
+
+// offsetParts returns the parts, with their start and end tangents, displaced by w along their RHS
+// normals. The parts are expected to be the result of simplification.
+func offsetParts(parts []Part, tangs [][][]float64, w float64) []Part {
 	n := len(parts)
-	tangs := p.Tangents()
 
 	// Convert tangents to scaled RHS normals
 	norms := make([][][]float64, n)
@@ -61,7 +196,7 @@ func (tp TraceProc) ProcessParts(p *Path) []Part {
 		norms[i][1] = []float64{w * tangs[i][1][1], -w * tangs[i][1][0]}
 	}
 
-	// Calculate the path by LineTransforming the parts and handling the joins
+	// Calculate the offset parts by LineTransforming the parts
 	rhs := make([]Part, n)
 	for i := range n {
 		part := parts[i]
@@ -74,37 +209,101 @@ func (tp TraceProc) ProcessParts(p *Path) []Part {
 
 		rhs[i] = xfm.Apply(part...)
 	}
+	return rhs
+}
 
-	// Compute the joins
-	nrhs := make([]Part, 0, 2*n)
-	nrhs = append(nrhs, rhs[0])
-	for i := 1; i < n; i++ {
-		last := nrhs[len(nrhs)-1]
-		// Check for knot first
-		npt := PartsIntersection(last, rhs[i], tp.Flatten)
-		if npt != nil {
-			// Tweak the end of nrhs[$] and start of rhs[i]
-			// Not strictly correct - should really figure out the t value for
-			// the point and then split part at t value to preserve the part's cp.
-			last[len(last)-1] = npt
-			rhs[i][0] = npt
-		} else {
-			nrhs = append(nrhs, tp.JoinFunc(last, parts[i][0], rhs[i])...)
+// maxOffsetDepth bounds the subdivision of a part when offsetting accurately.
+const maxOffsetDepth = 10
+
+// accurateOffsetParts returns the parts, subdivided where necessary, and their offsets by w along their
+// RHS normals to within tol. Each offset is either the transformed part, as used by offsetParts, or
+// the Hermite cubic, whichever is more accurate.
+func accurateOffsetParts(parts []Part, w, tol float64) ([]Part, []Part) {
+	nparts, rhs := make([]Part, 0, len(parts)), make([]Part, 0, len(parts))
+	for _, part := range parts {
+		if len(part) == 2 {
+			nparts = append(nparts, part)
+			rhs = append(rhs, offsetLine(part, w))
+			continue
 		}
-		nrhs = append(nrhs, rhs[i])
+		nparts, rhs = accurateOffsetPart(part, w, tol, 0, nparts, rhs)
 	}
+	return nparts, rhs
+}
 
-	if p.Closed() {
-		// Join the end points
-		last := nrhs[len(nrhs)-1]
-		npt := PartsIntersection(last, nrhs[0], tp.Flatten)
-		if npt != nil {
-			last[len(last)-1] = npt
-			nrhs[0][0] = npt
-		} else {
-			nrhs = append(nrhs, tp.JoinFunc(last, parts[0][0], nrhs[0])...)
+func accurateOffsetPart(part Part, w, tol float64, depth int, parts, rhs []Part) ([]Part, []Part) {
+	// Use the better of the transformed part and the Hermite cubic
+	d0, d1 := unitVec([]float64{0, 0}, partDirection(part, false)), unitVec([]float64{0, 0}, partDirection(part, true))
+	off := offsetParts([]Part{part}, [][][]float64{{d0, d1}}, w)[0]
+	err := offsetError(part, off, w)
+	if err > tol {
+		if hoff := hermiteOffset(part, w); offsetError(part, hoff, w) < err {
+			off, err = hoff, offsetError(part, hoff, w)
 		}
 	}
+	if depth == maxOffsetDepth || err <= tol {
+		return append(parts, part), append(rhs, off)
+	}
+	l, r := splitPart(part, 0.5)
+	parts, rhs = accurateOffsetPart(l, w, tol, depth+1, parts, rhs)
+	return accurateOffsetPart(r, w, tol, depth+1, parts, rhs)
+}
 
-	return nrhs
+// offsetLine returns the line displaced by w along its RHS normal.
+func offsetLine(part Part, w float64) Part {
+	dx, dy := unit(part[1][0]-part[0][0], part[1][1]-part[0][1])
+	nx, ny := w*dy, -w*dx
+	return Part{{part[0][0] + nx, part[0][1] + ny}, {part[1][0] + nx, part[1][1] + ny}}
+}
+
+// hermiteOffset returns the cubic with the same end points and end derivatives as the part
+// displaced by w along its RHS normal. The derivative of the offset is the part's derivative
+// scaled by 1 + w * k, where k is the curvature.
+func hermiteOffset(part Part, w float64) Part {
+	n := len(part) - 1
+	d1 := toDerivative(part)
+	d2 := toDerivative(d1)
+	end := func(pt, v, a, dir []float64) ([]float64, []float64) {
+		s := math.Hypot(v[0], v[1])
+		if s == 0 {
+			// Degenerate derivative, offset along the normal to dir
+			dx, dy := unit(dir[0], dir[1])
+			return []float64{pt[0] + w*dy, pt[1] - w*dx}, []float64{0, 0}
+		}
+		k := (v[0]*a[1] - v[1]*a[0]) / (s * s * s)
+		f := (1 + w*k) / 3
+		return []float64{pt[0] + w*v[1]/s, pt[1] - w*v[0]/s}, []float64{f * v[0], f * v[1]}
+	}
+	p0, v0 := end(part[0], d1[0], d2[0], partDirection(part, false))
+	p3, v3 := end(part[n], d1[n-1], d2[len(d2)-1], partDirection(part, true))
+	return Part{p0, {p0[0] + v0[0], p0[1] + v0[1]}, {p3[0] - v3[0], p3[1] - v3[1]}, p3}
+}
+
+// partDirection returns the direction of the part at its start, or its end, from the first control
+// point that differs from the end point.
+func partDirection(part Part, atEnd bool) []float64 {
+	n := len(part) - 1
+	for i := 1; i <= n; i++ {
+		if atEnd {
+			if !util.EqualsP(part[n-i], part[n]) {
+				return []float64{part[n][0] - part[n-i][0], part[n][1] - part[n-i][1]}
+			}
+		} else if !util.EqualsP(part[i], part[0]) {
+			return []float64{part[i][0] - part[0][0], part[i][1] - part[0][1]}
+		}
+	}
+	return []float64{1, 0}
+}
+
+// offsetError returns the maximum difference from w, at sampled points on the offset, of the distance
+// to the part.
+func offsetError(part, off Part, w float64) float64 {
+	w = math.Abs(w)
+	md := 0.0
+	for i := 1; i < offsetSamples; i++ {
+		pt := util.DeCasteljau(off, float64(i)/offsetSamples)
+		_, d2 := util.ClosestT(part, pt)
+		md = math.Max(md, math.Abs(math.Sqrt(d2)-w))
+	}
+	return md
 }
@@ -0,0 +1,37 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/util"
+	"math"
+)
+
+// Demonstrates the accuracy of TraceProc and AccurateTraceProc on an ellipse.
+func ExampleTraceProc() {
+	ellipse := g2d.Ellipse([]float64{100, 100}, 80, 20, 0)
+
+	maxError := func(trace *g2d.Path, w float64) float64 {
+		res := 0.0
+		for _, part := range trace.Parts() {
+			for i := range 11 {
+				pt := util.DeCasteljau(part, float64(i)/10)
+				res = math.Max(res, math.Abs(ellipse.DistanceTo(pt)-w))
+			}
+		}
+		return res
+	}
+
+	approx := g2d.TraceProc{Width: 10, JoinFunc: g2d.JoinRound}
+	accurate := g2d.AccurateTraceProc{Width: 10, Tolerance: 0.01, JoinFunc: g2d.JoinRound}
+	fmt.Printf("approximate error under 1 %v\n", maxError(ellipse.Process(approx)[0], 10) < 1)
+	fmt.Printf("accurate error under 0.01 %v\n", maxError(ellipse.Process(accurate)[0], 10) < 0.01)
+
+	// The sides of the stroke are traced at half its widths
+	sides := ellipse.Process(g2d.NewStrokeProcExt(8, -8, g2d.JoinRound, -0.01, g2d.CapButt))
+	fmt.Printf("accurate stroke error under 0.01 %v %v\n", maxError(sides[0], 4) < 0.01, maxError(sides[1], 4) < 0.01)
+	// Output:
+	// approximate error under 1 false
+	// accurate error under 0.01 true
+	// accurate stroke error under 0.01 true true
+}
@@ -0,0 +1,80 @@
+package graphics2d
+
+import "github.com/jphsd/graphics2d/util"
+
+// Triangulate returns the area of the shape, with its curves flattened to within flatten, as a set of
+// non-overlapping, counter-clockwise triangles suitable for meshes. The triangles are returned as
+// triples of indices into the vertices. The shape's paths are first resolved under its fill rule, so
+// crossing paths, overlapping paths and holes are all handled.
+func (s *Shape) Triangulate(flatten float64) ([][]float64, []int) {
+	rs := resolveShapes([]*Shape{s}, func(w []int) bool { return s.rule.Inside(w[0]) })
+
+	// Separate the outlines into outer contours and holes
+	outers, holes := [][][]float64{}, [][][]float64{}
+	oareas := []float64{}
+	for _, path := range rs.paths {
+		poly := flatPolygon(path, flatten)
+		if len(poly) < 3 {
+			continue
+		}
+		if area := path.Area(); area > 0 {
+			outers = append(outers, poly)
+			oareas = append(oareas, area)
+		} else {
+			holes = append(holes, poly)
+		}
+	}
+
+	// Assign each hole to the smallest outer contour containing it
+	ohs := make([][][][]float64, len(outers))
+	for _, hole := range holes {
+		best := -1
+		for i, outer := range outers {
+			if (best < 0 || oareas[i] < oareas[best]) && holeWithin(hole, outer) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			ohs[best] = append(ohs[best], hole)
+		}
+	}
+
+	verts, inds := [][]float64{}, []int{}
+	for i, outer := range outers {
+		off := len(verts)
+		verts = append(verts, outer...)
+		for _, hole := range ohs[i] {
+			verts = append(verts, hole...)
+		}
+		for _, ind := range util.Triangulate(outer, ohs[i]...) {
+			inds = append(inds, ind+off)
+		}
+	}
+	return verts, inds
+}
+
+// flatPolygon returns the points of the closed path flattened to within d, without repeated points.
+func flatPolygon(path *Path, d float64) [][]float64 {
+	res := [][]float64{}
+	for _, part := range path.Flatten(d).Parts() {
+		pt := part[0]
+		if len(res) == 0 || !util.EqualsP(res[len(res)-1], pt) {
+			res = append(res, pt)
+		}
+	}
+	for len(res) > 1 && util.EqualsP(res[0], res[len(res)-1]) {
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
+// holeWithin returns true if the hole lies within the outer polygon. Since the outlines don't cross,
+// any hole point found inside the outer polygon is sufficient.
+func holeWithin(hole, outer [][]float64) bool {
+	for _, pt := range hole {
+		if util.WindingNumber(pt, outer...) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/util"
+)
+
+// Demonstrates triangulating a shape with a hole into a mesh.
+func ExampleShape_Triangulate() {
+	outer := g2d.Polygon([]float64{0, 0}, []float64{100, 0}, []float64{100, 100}, []float64{0, 100})
+	hole := g2d.Polygon([]float64{25, 25}, []float64{25, 75}, []float64{75, 75}, []float64{75, 25})
+	shape := g2d.NewShape(outer, hole)
+
+	verts, inds := shape.Triangulate(0.1)
+	area := 0.0
+	for i := 0; i < len(inds); i += 3 {
+		area += util.TriArea(verts[inds[i]], verts[inds[i+1]], verts[inds[i+2]])
+	}
+	fmt.Printf("%d vertices, %d triangles, area %.2f\n", len(verts), len(inds)/3, area)
+
+	circle := g2d.NewShape(g2d.Circle([]float64{0, 0}, 50))
+	verts, inds = circle.Triangulate(0.1)
+	fmt.Printf("%d vertices, %d triangles\n", len(verts), len(inds)/3)
+	// Output:
+	// 8 vertices, 8 triangles, area 7500.00
+	// 64 vertices, 62 triangles
+}
+
+// Demonstrates triangulating a comb with three holes level on the right, where the nearest polygon
+// point to the right of each hole is blocked by the others, so the holes have to be bridged further.
+func ExampleShape_Triangulate_blocked() {
+	comb := g2d.Polygon([]float64{0, 0}, []float64{20, 0}, []float64{20, 20}, []float64{11, 20},
+		[]float64{11, 12}, []float64{10, 12}, []float64{10, 20}, []float64{7, 20}, []float64{7, 12},
+		[]float64{6, 12}, []float64{6, 20}, []float64{3, 20}, []float64{3, 18}, []float64{1, 18},
+		[]float64{1, 20}, []float64{0, 20})
+	shape := g2d.NewShape(comb,
+		g2d.Polygon([]float64{13, 4}, []float64{13, 6}, []float64{14, 6}, []float64{14, 4}),
+		g2d.Polygon([]float64{12, 12}, []float64{12, 13}, []float64{14, 13}, []float64{14, 12}),
+		g2d.Polygon([]float64{13, 10}, []float64{13, 11}, []float64{14, 11}, []float64{14, 10}))
+
+	verts, inds := shape.Triangulate(0.1)
+	area := 0.0
+	for i := 0; i < len(inds); i += 3 {
+		area += util.TriArea(verts[inds[i]], verts[inds[i+1]], verts[inds[i+2]])
+	}
+	fmt.Printf("%d vertices, %d triangles, area %.2f\n", len(verts), len(inds)/3, area)
+	// Output: 28 vertices, 28 triangles, area 375.00
+}
@@ -17,17 +17,19 @@ func NewTriangleWaveProc(lambda, amplitude float64) TriangleWaveProc {
 
 // Process implements the PathProcessor interface.
 func (tp TriangleWaveProc) Process(p *Path) []*Path {
-	// Chunk up path into pieces
-	pp1 := NewMunchProc(tp.HalfLambda).Process(p)
-	p1, _ := ConcatenatePaths(pp1...)
+	// Points along the path, half a wave length apart
+	pts := p.pointsEvery(tp.HalfLambda)
 
-	n := len(p1.steps)
-	last := p1.steps[0][0]
+	n := len(pts)
+	if n == 0 {
+		return []*Path{p}
+	}
+	last := pts[0]
 	path := NewPath(last)
 	left := !tp.Flip
 	for i := 1; i < n; i++ {
 		// Each step is a half wave
-		cur := p1.steps[i][0]
+		cur := pts[i]
 		dx, dy := cur[0]-last[0], cur[1]-last[1]
 		ndx, ndy := dy*tp.Scale, -dx*tp.Scale
 		if left {
@@ -0,0 +1,249 @@
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// A polynomial of degree n over [0,1] can be described by its n+1 Bernstein coefficients. The
+// x and y values of the points of a Bezier curve are such coefficients, which allows the functions
+// below to answer questions about curves (roots, products, integrals) without converting them to
+// the power basis.
+
+// BernsteinEval evaluates the polynomial described by the Bernstein coefficients at t.
+func BernsteinEval(c []float64, t float64) float64 {
+	n := len(c)
+	if n == 0 {
+		return 0
+	}
+	tmp := make([]float64, n)
+	copy(tmp, c)
+	omt := 1 - t
+	for k := n - 1; k > 0; k-- {
+		for i := range k {
+			tmp[i] = omt*tmp[i] + t*tmp[i+1]
+		}
+	}
+	return tmp[0]
+}
+
+// BernsteinSplit splits the polynomial at t into two sets of coefficients, one describing [0,t] and
+// the other [t,1], both reparameterized to [0,1].
+func BernsteinSplit(c []float64, t float64) ([]float64, []float64) {
+	n := len(c)
+	left, right := make([]float64, n), make([]float64, n)
+	tmp := make([]float64, n)
+	copy(tmp, c)
+	omt := 1 - t
+	for k := range n {
+		left[k] = tmp[0]
+		right[n-1-k] = tmp[n-1-k]
+		for i := 0; i < n-1-k; i++ {
+			tmp[i] = omt*tmp[i] + t*tmp[i+1]
+		}
+	}
+	return left, right
+}
+
+// BernsteinMul returns the Bernstein coefficients of the product of the two polynomials.
+// The degree of the result is the sum of the degrees of a and b.
+func BernsteinMul(a, b []float64) []float64 {
+	n, m := len(a)-1, len(b)-1
+	res := make([]float64, n+m+1)
+	for i, av := range a {
+		ci := Binomial(n, i)
+		for j, bv := range b {
+			res[i+j] += ci * Binomial(m, j) * av * bv
+		}
+	}
+	for k := range res {
+		res[k] /= Binomial(n+m, k)
+	}
+	return res
+}
+
+// BernsteinIntegral returns the integral of the polynomial over [0,1], which is the mean of
+// its coefficients.
+func BernsteinIntegral(c []float64) float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range c {
+		sum += v
+	}
+	return sum / float64(len(c))
+}
+
+// Binomial returns n choose k as a float64.
+func Binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	res := 1.0
+	for i := 1; i <= k; i++ {
+		res = res * float64(n-k+i) / float64(i)
+	}
+	return res
+}
+
+// RootEpsilon is the interval size in t at which root isolation stops subdividing.
+const RootEpsilon = 1e-12
+
+// BernsteinRoots returns the sorted values of t in [0,1] for which the polynomial described by the
+// Bernstein coefficients is zero. The variation diminishing property of the coefficients is used to
+// isolate the roots by subdivision, each isolated root is then found by bisection. Repeated roots,
+// such as those found where a curve touches a line, are reported once. A polynomial that is zero
+// everywhere has no isolated roots and nil is returned.
+func BernsteinRoots(c []float64) []float64 {
+	if len(c) < 2 {
+		return nil
+	}
+	res := bernsteinRoots(c, 0, 1, 0, nil)
+	if len(res) < 2 {
+		return res
+	}
+	sort.Float64s(res)
+	// Remove duplicates found at subdivision boundaries
+	nres := res[:1]
+	for _, t := range res[1:] {
+		if t-nres[len(nres)-1] > 1e-9 {
+			nres = append(nres, t)
+		}
+	}
+	return nres
+}
+
+func bernsteinRoots(c []float64, t0, t1 float64, depth int, res []float64) []float64 {
+	n := len(c) - 1
+	changes, prev := 0, 0
+	for _, v := range c {
+		s := sign(v)
+		if s == 0 {
+			continue
+		}
+		if prev != 0 && s != prev {
+			changes++
+		}
+		prev = s
+	}
+	if prev == 0 {
+		// Identically zero
+		return res
+	}
+	if changes == 0 {
+		// Only the end points can be roots
+		if c[0] == 0 {
+			res = append(res, t0)
+		}
+		if c[n] == 0 {
+			res = append(res, t1)
+		}
+		return res
+	}
+	if t1-t0 < RootEpsilon || depth > 64 {
+		return append(res, (t0+t1)/2)
+	}
+	if changes == 1 && c[0] != 0 && c[n] != 0 {
+		// Exactly one root in the interval
+		u := bernsteinBisect(c)
+		return append(res, t0+u*(t1-t0))
+	}
+	l, r := BernsteinSplit(c, 0.5)
+	tm := (t0 + t1) / 2
+	res = bernsteinRoots(l, t0, tm, depth+1, res)
+	return bernsteinRoots(r, tm, t1, depth+1, res)
+}
+
+// Assumes c[0] and c[n] have opposite signs.
+func bernsteinBisect(c []float64) float64 {
+	lo, hi := 0.0, 1.0
+	slo := sign(c[0])
+	for hi-lo > RootEpsilon {
+		m := (lo + hi) / 2
+		v := BernsteinEval(c, m)
+		if v == 0 {
+			return m
+		}
+		if sign(v) == slo {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+	return (lo + hi) / 2
+}
+
+func sign(v float64) int {
+	if v > 0 {
+		return 1
+	}
+	if v < 0 {
+		return -1
+	}
+	return 0
+}
+
+// BezierX returns the Bernstein coefficients of the x values of a curve.
+func BezierX(pts [][]float64) []float64 {
+	res := make([]float64, len(pts))
+	for i, pt := range pts {
+		res[i] = pt[0]
+	}
+	return res
+}
+
+// BezierY returns the Bernstein coefficients of the y values of a curve.
+func BezierY(pts [][]float64) []float64 {
+	res := make([]float64, len(pts))
+	for i, pt := range pts {
+		res[i] = pt[1]
+	}
+	return res
+}
+
+// BernsteinDerivative returns the Bernstein coefficients of the derivative of the polynomial.
+func BernsteinDerivative(c []float64) []float64 {
+	n := len(c) - 1
+	if n < 1 {
+		return []float64{0}
+	}
+	res := make([]float64, n)
+	for i := range n {
+		res[i] = float64(n) * (c[i+1] - c[i])
+	}
+	return res
+}
+
+// ClosestT returns the t value in [0,1] of the point on the curve closest to pt, and the distance
+// squared. It solves (B(t) - pt).B'(t) = 0 rather than searching, so it finds the global minimum.
+func ClosestT(pts [][]float64, pt []float64) (float64, float64) {
+	n := len(pts)
+	if n == 1 {
+		return 0, DistanceESquared(pts[0], pt)
+	}
+	xs, ys := BezierX(pts), BezierY(pts)
+	for i := range n {
+		xs[i] -= pt[0]
+		ys[i] -= pt[1]
+	}
+	dxs, dys := BernsteinDerivative(xs), BernsteinDerivative(ys)
+	f := BernsteinMul(xs, dxs)
+	fy := BernsteinMul(ys, dys)
+	for i := range f {
+		f[i] += fy[i]
+	}
+	cands := append(BernsteinRoots(f), 0, 1)
+	bt, bd := 0.0, math.MaxFloat64
+	for _, t := range cands {
+		x, y := BernsteinEval(xs, t), BernsteinEval(ys, t)
+		d := x*x + y*y
+		if d < bd {
+			bt, bd = t, d
+		}
+	}
+	return bt, bd
+}
@@ -0,0 +1,307 @@
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// Delaunay triangulation, using the Bowyer-Watson algorithm, and Voronoi cells derived from it.
+
+// Delaunay returns the Delaunay triangulation of the points as triples of indices into them. The
+// triangles are counter-clockwise and cover the points' convex hull. Points coincident with an earlier
+// point are ignored.
+func Delaunay(pts ...[]float64) []int {
+	n := len(pts)
+	if n < 3 {
+		return nil
+	}
+
+	// Insert the points in strips, alternately up and down, so each is near the last
+	bb := BoundingBox(pts...)
+	w := math.Max(bb[1][0]-bb[0][0], bb[1][1]-bb[0][1]) / math.Max(1, math.Sqrt(float64(n)/4))
+	if w == 0 {
+		return nil
+	}
+	order := make([]int, n)
+	strip := make([]int, n)
+	for i, p := range pts {
+		order[i], strip[i] = i, int((p[0]-bb[0][0])/w)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if strip[i] != strip[j] {
+			return strip[i] < strip[j]
+		}
+		if strip[i]%2 == 0 {
+			return pts[i][1] < pts[j][1]
+		}
+		return pts[i][1] > pts[j][1]
+	})
+
+	// Start with the first three points that aren't collinear
+	k1, k2 := -1, -1
+	for k := 1; k < n && k2 < 0; k++ {
+		p := pts[order[k]]
+		if k1 < 0 {
+			if !EqualsP(pts[order[0]], p) {
+				k1 = k
+			}
+		} else if !Equals(TriArea(pts[order[0]], pts[order[k1]], p), 0) {
+			k2 = k
+		}
+	}
+	if k2 < 0 {
+		return nil
+	}
+	d := newDelaunayMesh(pts, order[0], order[k1], order[k2])
+	for k, i := range order {
+		if k != 0 && k != k1 && k != k2 {
+			d.insert(i)
+		}
+	}
+
+	// Drop the triangles that use the vertex at infinity
+	res := make([]int, 0, 6*n)
+	for t := range len(d.dead) {
+		v := d.tv[3*t : 3*t+3]
+		if !d.dead[t] && v[0] != ghost && v[1] != ghost && v[2] != ghost {
+			res = append(res, v...)
+		}
+	}
+	return res
+}
+
+// ghost is the index of the vertex at infinity, which every convex hull edge forms a triangle with.
+// Using it in place of a large enclosing triangle means no triangles on the hull are ever lost.
+const ghost = -1
+
+// delaunayMesh holds the triangles as triples of point indices, tv, and the triangles adjacent to
+// them, tn, where the triangle across the edge from tv[3t+k] to tv[3t+(k+1)%3] is tn[3t+k].
+type delaunayMesh struct {
+	pts    [][]float64
+	tv, tn []int
+	dead   []bool
+	last   int   // the triangle to start looking for the next point from
+	mark   []int // the point being inserted when the triangle was last found to be in conflict
+	cavity []int
+}
+
+// newDelaunayMesh returns a mesh of the triangle a, b, c and the three triangles joining its edges
+// to the vertex at infinity.
+func newDelaunayMesh(pts [][]float64, a, b, c int) *delaunayMesh {
+	if TriArea(pts[a], pts[b], pts[c]) < 0 {
+		b, c = c, b
+	}
+	d := &delaunayMesh{pts: pts}
+	d.tv = []int{a, b, c, b, a, ghost, c, b, ghost, a, c, ghost}
+	d.tn = []int{1, 2, 3, 0, 3, 2, 0, 1, 3, 0, 2, 1}
+	d.dead = make([]bool, 4)
+	d.mark = []int{-1, -1, -1, -1}
+	return d
+}
+
+// insert adds point i to the mesh by removing the triangles whose circumcircles contain it and
+// joining the edges of the cavity they leave to it.
+func (d *delaunayMesh) insert(i int) {
+	p := d.pts[i]
+	t := d.locate(p)
+	d.cavity = append(d.cavity[:0], t)
+	d.mark[t] = i
+	for c := 0; c < len(d.cavity); c++ {
+		t := d.cavity[c]
+		for k := range 3 {
+			if v := d.tv[3*t+k]; v != ghost && EqualsP(p, d.pts[v]) {
+				// Coincident with an earlier point
+				for _, t := range d.cavity {
+					d.mark[t] = -1
+				}
+				return
+			}
+			o := d.tn[3*t+k]
+			if d.mark[o] != i && d.conflicts(o, p) {
+				d.mark[o] = i
+				d.cavity = append(d.cavity, o)
+			}
+		}
+	}
+
+	// Replace the cavity with triangles from its boundary edges to the point
+	start, end := map[int]int{}, map[int]int{}
+	for _, t := range d.cavity {
+		d.dead[t] = true
+		for k := range 3 {
+			o := d.tn[3*t+k]
+			if d.mark[o] == i {
+				continue
+			}
+			a, b := d.tv[3*t+k], d.tv[3*t+(k+1)%3]
+			nt := len(d.dead)
+			d.tv = append(d.tv, a, b, i)
+			d.tn = append(d.tn, o, -1, -1)
+			d.dead = append(d.dead, false)
+			d.mark = append(d.mark, -1)
+			for ok := range 3 {
+				if d.tn[3*o+ok] == t {
+					d.tn[3*o+ok] = nt
+				}
+			}
+			start[a], end[b] = nt, nt
+			if a != ghost && b != ghost {
+				d.last = nt
+			}
+		}
+	}
+	for a, nt := range start {
+		b := d.tv[3*nt+1]
+		d.tn[3*nt+1], d.tn[3*nt+2] = start[b], end[a]
+	}
+}
+
+// locate returns a triangle in conflict with p, found by walking across the mesh from the last
+// triangle added towards p. If the walk leaves the convex hull, then the triangle with the vertex at
+// infinity beyond the hull edge it crosses is returned.
+func (d *delaunayMesh) locate(p []float64) int {
+	t := d.last
+	for range len(d.dead) {
+		next := -1
+		for k := range 3 {
+			a, b := d.tv[3*t+k], d.tv[3*t+(k+1)%3]
+			if TriArea(d.pts[a], d.pts[b], p) < 0 {
+				next = d.tn[3*t+k]
+				break
+			}
+		}
+		if next < 0 {
+			return t
+		}
+		t = next
+		if d.isGhost(t) {
+			return t
+		}
+	}
+
+	// Only reached if rounding errors send the walk around in circles
+	for t := range d.dead {
+		if !d.dead[t] && d.conflicts(t, p) {
+			return t
+		}
+	}
+	return d.last
+}
+
+// isGhost returns true if triangle t uses the vertex at infinity.
+func (d *delaunayMesh) isGhost(t int) bool {
+	return d.tv[3*t] == ghost || d.tv[3*t+1] == ghost || d.tv[3*t+2] == ghost
+}
+
+// conflicts returns true if p is within the circumcircle of triangle t. For a triangle with the vertex
+// at infinity, this is the open half-plane beyond its hull edge plus the edge itself.
+func (d *delaunayMesh) conflicts(t int, p []float64) bool {
+	v := d.tv[3*t : 3*t+3]
+	for k := range 3 {
+		if v[k] != ghost {
+			continue
+		}
+		a, b := d.pts[v[(k+1)%3]], d.pts[v[(k+2)%3]]
+		side := TriArea(a, b, p)
+		if side != 0 {
+			return side > 0
+		}
+		return DotProduct(a, p, p, b) > 0
+	}
+	return inCircle(d.pts[v[0]], d.pts[v[1]], d.pts[v[2]], p) > 0
+}
+
+// inCircle returns a value that is positive if p lies within the circumcircle of the
+// counter-clockwise triangle a, b, c, negative if outside and zero if on it.
+func inCircle(a, b, c, p []float64) float64 {
+	ax, ay := a[0]-p[0], a[1]-p[1]
+	bx, by := b[0]-p[0], b[1]-p[1]
+	cx, cy := c[0]-p[0], c[1]-p[1]
+	return (ax*ax+ay*ay)*(bx*cy-cx*by) - (bx*bx+by*by)*(ax*cy-cx*ay) + (cx*cx+cy*cy)*(ax*by-bx*ay)
+}
+
+// VoronoiCells returns the Voronoi cell of each of the points clipped to the rectangle bb, in the same
+// order as the points. The cells are convex, counter-clockwise polygons. A point coincident with an
+// earlier one has an empty cell, as does a point whose cell lies outside of bb.
+func VoronoiCells(bb [][]float64, pts ...[]float64) [][][]float64 {
+	n := len(pts)
+	res := make([][][]float64, n)
+	if n == 0 {
+		return res
+	}
+
+	// A cell is bounded by the bisectors between its point and its Delaunay neighbors
+	nbrs := make([]map[int]bool, n)
+	for i := range n {
+		nbrs[i] = map[int]bool{}
+	}
+	tris := Delaunay(pts...)
+	for i := 0; i < len(tris); i += 3 {
+		for k := range 3 {
+			a, b := tris[i+k], tris[i+(k+1)%3]
+			nbrs[a][b], nbrs[b][a] = true, true
+		}
+	}
+	for i := range n {
+		if len(tris) > 0 && len(nbrs[i]) > 0 {
+			continue
+		}
+		// Collinear points or a duplicate
+		for j := range n {
+			if j == i {
+				continue
+			}
+			if EqualsP(pts[i], pts[j]) {
+				if j < i {
+					nbrs[i] = nil
+					break
+				}
+				continue
+			}
+			nbrs[i][j] = true
+		}
+	}
+
+	for i, p := range pts {
+		if nbrs[i] == nil {
+			continue
+		}
+		cell := [][]float64{
+			{bb[0][0], bb[0][1]}, {bb[1][0], bb[0][1]},
+			{bb[1][0], bb[1][1]}, {bb[0][0], bb[1][1]},
+		}
+		for j := range nbrs[i] {
+			q := pts[j]
+			// Keep the side of the bisector nearest p
+			nx, ny := q[0]-p[0], q[1]-p[1]
+			c := nx*(p[0]+q[0])/2 + ny*(p[1]+q[1])/2
+			cell = clipHalfPlane(cell, nx, ny, c)
+			if len(cell) == 0 {
+				break
+			}
+		}
+		if len(cell) > 2 {
+			res[i] = cell
+		}
+	}
+	return res
+}
+
+// clipHalfPlane returns the part of the convex polygon where a*x + b*y <= c.
+func clipHalfPlane(poly [][]float64, a, b, c float64) [][]float64 {
+	n := len(poly)
+	res := make([][]float64, 0, n+1)
+	for i := range n {
+		p, q := poly[i], poly[(i+1)%n]
+		dp, dq := a*p[0]+b*p[1]-c, a*q[0]+b*q[1]-c
+		if dp <= 0 {
+			res = append(res, p)
+		}
+		if (dp < 0 && dq > 0) || (dp > 0 && dq < 0) {
+			t := dp / (dp - dq)
+			res = append(res, []float64{Lerp(t, p[0], q[0]), Lerp(t, p[1], q[1])})
+		}
+	}
+	return res
+}
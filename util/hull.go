@@ -0,0 +1,99 @@
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// ConvexHull returns the convex hull of the points using Andrew's monotone chain algorithm. The hull
+// is counter-clockwise (i.e. has a positive area) and collinear and coincident points are omitted.
+func ConvexHull(pts ...[]float64) [][]float64 {
+	spts := make([][]float64, len(pts))
+	copy(spts, pts)
+	sort.Slice(spts, func(i, j int) bool {
+		return spts[i][0] < spts[j][0] || spts[i][0] == spts[j][0] && spts[i][1] < spts[j][1]
+	})
+	// Remove coincident points
+	n := 0
+	for _, pt := range spts {
+		if n > 0 && EqualsP(spts[n-1], pt) {
+			continue
+		}
+		spts[n] = pt
+		n++
+	}
+	spts = spts[:n]
+	if n < 3 {
+		return spts
+	}
+
+	res := make([][]float64, 0, 2*n)
+	// Lower hull
+	for _, pt := range spts {
+		for len(res) > 1 && CrossProduct(res[len(res)-2], pt, res[len(res)-1]) <= 0 {
+			res = res[:len(res)-1]
+		}
+		res = append(res, pt)
+	}
+	// Upper hull
+	lower := len(res) + 1
+	for i := n - 2; i >= 0; i-- {
+		pt := spts[i]
+		for len(res) >= lower && CrossProduct(res[len(res)-2], pt, res[len(res)-1]) <= 0 {
+			res = res[:len(res)-1]
+		}
+		res = append(res, pt)
+	}
+	// Last point is the same as the first
+	return res[:len(res)-1]
+}
+
+// MinAreaRect returns the corners of the minimum area rectangle enclosing the convex hull, using
+// rotating calipers, and the rectangle's rotation angle in [-Pi/4, Pi/4). The corners are
+// counter-clockwise, starting with the one that has the minimum x and y in the rotated frame, so the
+// first two corners lie along the angle. If all the points coincide, then the corners are all that
+// point and the angle is 0.
+func MinAreaRect(hull ...[]float64) ([][]float64, float64) {
+	n := len(hull)
+	if n == 0 {
+		return nil, 0
+	}
+	best, bth := math.MaxFloat64, 0.0
+	var bb [][]float64
+	for i := range n {
+		a, b := hull[i], hull[(i+1)%n]
+		if EqualsP(a, b) && n > 1 {
+			continue
+		}
+		th := math.Atan2(b[1]-a[1], b[0]-a[0])
+		// Rectangles repeat every quarter turn
+		th -= math.Floor((th+math.Pi/4)/(math.Pi/2)) * math.Pi / 2
+		rbb := rotatedBB(th, hull)
+		area := (rbb[1][0] - rbb[0][0]) * (rbb[1][1] - rbb[0][1])
+		if area < best {
+			best, bth, bb = area, th, rbb
+		}
+	}
+	if bb == nil {
+		// All the points are coincident
+		bb = [][]float64{hull[0], hull[0]}
+	}
+	c, s := math.Cos(bth), math.Sin(bth)
+	corners := [][]float64{
+		{bb[0][0], bb[0][1]}, {bb[1][0], bb[0][1]}, {bb[1][0], bb[1][1]}, {bb[0][0], bb[1][1]},
+	}
+	for i, pt := range corners {
+		corners[i] = []float64{pt[0]*c - pt[1]*s, pt[0]*s + pt[1]*c}
+	}
+	return corners, bth
+}
+
+// rotatedBB returns the bounding box of the points rotated by -th.
+func rotatedBB(th float64, pts [][]float64) [][]float64 {
+	c, s := math.Cos(th), math.Sin(th)
+	rpts := make([][]float64, len(pts))
+	for i, pt := range pts {
+		rpts[i] = []float64{pt[0]*c + pt[1]*s, -pt[0]*s + pt[1]*c}
+	}
+	return BoundingBox(rpts...)
+}
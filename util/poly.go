@@ -8,13 +8,22 @@ const (
 )
 
 // WindingRule defines how edges in an edge crossing polygon are treated.
+//
+// Deprecated: WindingRule is shared by all callers of PointInPoly, so concurrent callers can't use
+// different rules. Use PointInPolyRule, or Path.PointInPathRule, with an explicit rule instead.
 var WindingRule = OddEven
 
 // See https://wrfranklin.org/Research/Short_Notes/pnpoly.html for another implementation of the OE rule.
 
 // PointInPoly returns true is a point is within the polygon defined by the list of vertices according to the
-// setting of WindingRule (OddEven or NonZero).
+// setting of WindingRule (OddEven or NonZero). Use PointInPolyRule to supply the rule explicitly.
 func PointInPoly(pt []float64, poly ...[]float64) bool {
+	return PointInPolyRule(WindingRule, pt, poly...)
+}
+
+// PointInPolyRule returns true is a point is within the polygon defined by the list of vertices according to the
+// supplied winding rule. Unlike PointInPoly, it's not affected by the setting of WindingRule.
+func PointInPolyRule(rule wrule, pt []float64, poly ...[]float64) bool {
 	n := len(poly)
 	if n == 0 {
 		return false
@@ -52,7 +61,7 @@ func PointInPoly(pt []float64, poly ...[]float64) bool {
 			t := (y - y0) / (y1 - y0)
 			xi := Lerp(t, x0, x1)
 			if xi < x {
-				if WindingRule == OddEven {
+				if rule == OddEven {
 					lsum++
 				} else if up {
 					lsum++
@@ -60,7 +69,7 @@ func PointInPoly(pt []float64, poly ...[]float64) bool {
 					lsum--
 				}
 			} else if x < xi {
-				if WindingRule == OddEven {
+				if rule == OddEven {
 					rsum++
 				} else if up {
 					rsum++
@@ -72,8 +81,39 @@ func PointInPoly(pt []float64, poly ...[]float64) bool {
 		prev = cur
 	}
 
-	if WindingRule == OddEven {
+	if rule == OddEven {
 		return lsum%2 == 1 && rsum%2 == 1
 	}
 	return lsum != 0 && rsum != 0
 }
+
+// WindingNumber returns the sum of the signed edge crossings made by a ray projected from the point in
+// the +ve x direction. Edges going from lower to higher y count +1, and from higher to lower y, -1.
+// Since the sums from several polygons can be added together, this allows a winding rule to be applied
+// to a set of polygons rather than just one.
+func WindingNumber(pt []float64, poly ...[]float64) int {
+	n := len(poly)
+	if n == 0 {
+		return 0
+	}
+
+	x, y := pt[0], pt[1]
+	sum := 0
+	prev := poly[n-1]
+	for _, cur := range poly {
+		y0, y1 := prev[1], cur[1]
+		// Half open interval so a ray through a vertex is only counted once
+		if y0 <= y && y < y1 || y1 <= y && y < y0 {
+			t := (y - y0) / (y1 - y0)
+			if Lerp(t, prev[0], cur[0]) > x {
+				if y1 > y0 {
+					sum++
+				} else {
+					sum--
+				}
+			}
+		}
+		prev = cur
+	}
+	return sum
+}
@@ -0,0 +1,287 @@
+package util
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Polyline simplification. Both algorithms take a tolerance in the same units as the points. If
+// preserve is set, then a closed polyline is kept as at least a triangle, and points are retained
+// where their removal would cause the polyline to cross itself (assuming it didn't to begin with).
+
+// DouglasPeucker returns the points retained by the Douglas-Peucker algorithm. Points within tol of
+// the line joining the retained points either side of them are removed.
+func DouglasPeucker(tol float64, closed, preserve bool, pts ...[]float64) [][]float64 {
+	n := len(pts)
+	if n < 3 {
+		return pts
+	}
+	keep := make([]bool, n)
+	if closed {
+		// Split the loop at the point furthest from the first
+		far, fd := 0, 0.0
+		for i := 1; i < n; i++ {
+			if d := DistanceESquared(pts[0], pts[i]); d > fd {
+				far, fd = i, d
+			}
+		}
+		keep[0], keep[far] = true, true
+		dpMark(tol, pts, 0, far, keep)
+		loop := append(append([][]float64{}, pts[far:]...), pts[0])
+		lkeep := make([]bool, len(loop))
+		dpMark(tol, loop, 0, len(loop)-1, lkeep)
+		copy(keep[far+1:], lkeep[1:len(lkeep)-1])
+		if preserve {
+			ensureTriangle(pts, keep)
+		}
+	} else {
+		keep[0], keep[n-1] = true, true
+		dpMark(tol, pts, 0, n-1, keep)
+	}
+	if preserve {
+		// Restore the furthest point in any segment that crosses another until there are none
+		for {
+			inds := keptIndices(keep)
+			i, j, ok := findCrossing(pts, inds, closed)
+			if !ok {
+				break
+			}
+			changed := false
+			for _, k := range []int{i, j} {
+				s, e := inds[k], inds[(k+1)%len(inds)]
+				if f := furthest(pts, s, e); f >= 0 {
+					keep[f] = true
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+	return keptPoints(pts, keep)
+}
+
+// dpMark marks the points between s and e that are to be kept.
+func dpMark(tol float64, pts [][]float64, s, e int, keep []bool) {
+	if e-s < 2 {
+		return
+	}
+	f := furthest(pts, s, e)
+	if f < 0 || distanceToSegment(pts[f], pts[s], pts[e]) <= tol {
+		return
+	}
+	keep[f] = true
+	dpMark(tol, pts, s, f, keep)
+	dpMark(tol, pts, f, e, keep)
+}
+
+// furthest returns the index of the point between s and e, wrapping if e < s, that is furthest from
+// the segment s-e, or -1 if there are none.
+func furthest(pts [][]float64, s, e int) int {
+	n := len(pts)
+	res, rd := -1, -1.0
+	for i := (s + 1) % n; i != e; i = (i + 1) % n {
+		if d := distanceToSegment(pts[i], pts[s], pts[e]); d > rd {
+			res, rd = i, d
+		}
+	}
+	return res
+}
+
+// distanceToSegment returns the distance of p from the line segment a-b.
+func distanceToSegment(p, a, b []float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return DistanceE(p, a)
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(a[0]+t*dx-p[0], a[1]+t*dy-p[1])
+}
+
+// ensureTriangle marks extra points, if needed, so that at least three are kept.
+func ensureTriangle(pts [][]float64, keep []bool) {
+	for len(keptIndices(keep)) < 3 {
+		inds := keptIndices(keep)
+		best, bd := -1, -1.0
+		for k := range inds {
+			s, e := inds[k], inds[(k+1)%len(inds)]
+			if f := furthest(pts, s, e); f >= 0 {
+				if d := distanceToSegment(pts[f], pts[s], pts[e]); d > bd {
+					best, bd = f, d
+				}
+			}
+		}
+		if best < 0 {
+			return
+		}
+		keep[best] = true
+	}
+}
+
+func keptIndices(keep []bool) []int {
+	res := []int{}
+	for i, k := range keep {
+		if k {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func keptPoints(pts [][]float64, keep []bool) [][]float64 {
+	res := [][]float64{}
+	for i, k := range keep {
+		if k {
+			res = append(res, pts[i])
+		}
+	}
+	return res
+}
+
+// findCrossing returns the indices into inds of the first two non-adjacent segments that intersect.
+func findCrossing(pts [][]float64, inds []int, closed bool) (int, int, bool) {
+	ns := len(inds) - 1
+	if closed {
+		ns++
+	}
+	for i := range ns {
+		a0, a1 := pts[inds[i]], pts[inds[(i+1)%len(inds)]]
+		for j := i + 2; j < ns; j++ {
+			if closed && i == 0 && j == ns-1 {
+				// Adjacent
+				continue
+			}
+			b0, b1 := pts[inds[j]], pts[inds[(j+1)%len(inds)]]
+			if SegmentsIntersect(a0, a1, b0, b1) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// SegmentsIntersect returns true if the line segments a0-a1 and b0-b1 intersect or touch.
+func SegmentsIntersect(a0, a1, b0, b1 []float64) bool {
+	d1, d2 := CrossProduct(a0, a1, b0), CrossProduct(a0, a1, b1)
+	d3, d4 := CrossProduct(b0, b1, a0), CrossProduct(b0, b1, a1)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	onSeg := func(p, q, r []float64) bool {
+		return math.Min(p[0], q[0]) <= r[0] && r[0] <= math.Max(p[0], q[0]) &&
+			math.Min(p[1], q[1]) <= r[1] && r[1] <= math.Max(p[1], q[1])
+	}
+	return d1 == 0 && onSeg(a0, a1, b0) || d2 == 0 && onSeg(a0, a1, b1) ||
+		d3 == 0 && onSeg(b0, b1, a0) || d4 == 0 && onSeg(b0, b1, a1)
+}
+
+// VisvalingamWhyatt returns the points retained by the Visvalingam-Whyatt algorithm. The point forming
+// the smallest area triangle with its neighbors is repeatedly removed until all the remaining
+// triangles have an area of at least tol * tol. For closed polylines the end points are candidates for
+// removal too.
+func VisvalingamWhyatt(tol float64, closed, preserve bool, pts ...[]float64) [][]float64 {
+	n := len(pts)
+	if n < 3 {
+		return pts
+	}
+	prev, next := make([]int, n), make([]int, n)
+	for i := range n {
+		prev[i], next[i] = i-1, i+1
+	}
+	if closed {
+		prev[0], next[n-1] = n-1, 0
+	}
+	minPts := 2
+	if closed && preserve {
+		minPts = 3
+	}
+
+	area := func(i int) float64 {
+		return math.Abs(TriArea(pts[prev[i]], pts[i], pts[next[i]]))
+	}
+	removed := make([]bool, n)
+	version := make([]int, n)
+	h := &vwHeap{}
+	for i := range n {
+		if prev[i] >= 0 && next[i] < n {
+			heap.Push(h, vwEntry{i, area(i), 0})
+		}
+	}
+
+	limit := tol * tol
+	count := n
+	for h.Len() > 0 && count > minPts {
+		e := heap.Pop(h).(vwEntry)
+		if removed[e.i] || e.version != version[e.i] {
+			continue
+		}
+		if e.area >= limit {
+			break
+		}
+		p, q := prev[e.i], next[e.i]
+		if preserve && vwCrosses(pts, next, removed, e.i, p, q) {
+			// Leave in place until one of its neighbors changes
+			continue
+		}
+		removed[e.i] = true
+		count--
+		next[p], prev[q] = q, p
+		for _, j := range []int{p, q} {
+			if prev[j] >= 0 && next[j] < n {
+				version[j]++
+				// Effective area is never less than that of the point just removed
+				heap.Push(h, vwEntry{j, math.Max(area(j), e.area), version[j]})
+			}
+		}
+	}
+
+	res := [][]float64{}
+	for i, r := range removed {
+		if !r {
+			res = append(res, pts[i])
+		}
+	}
+	return res
+}
+
+// vwCrosses returns true if the segment p-q, formed by removing point i, would intersect any of the
+// other remaining segments.
+func vwCrosses(pts [][]float64, next []int, removed []bool, i, p, q int) bool {
+	n := len(pts)
+	for s := range n {
+		if removed[s] || s == i || s == p || s == q || next[s] >= n || next[s] < 0 {
+			continue
+		}
+		e := next[s]
+		if e == p {
+			continue
+		}
+		if SegmentsIntersect(pts[p], pts[q], pts[s], pts[e]) {
+			return true
+		}
+	}
+	return false
+}
+
+type vwEntry struct {
+	i       int
+	area    float64
+	version int
+}
+
+type vwHeap []vwEntry
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *vwHeap) Push(x any)        { *h = append(*h, x.(vwEntry)) }
+func (h *vwHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -1,6 +1,9 @@
 package util
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // TriArea returns the signed area of a triangle by finding the determinant of
 // M = {{p1[0] p1[1] 1}
@@ -131,3 +134,189 @@ func Dejitter(closed bool, pts ...[]float64) [][]float64 {
 	pts[n] = nil
 	return pts[:n]
 }
+
+// Triangulate returns the triangles covering the polygon described by outer with the holes removed,
+// using ear clipping. The outer polygon must be counter-clockwise and the holes clockwise, and none of
+// them may cross. Each hole is first joined to the polygon by a pair of coincident edges from its
+// rightmost point to a visible polygon point. The triangles are returned as triples of indices into the
+// points of outer followed by those of each hole in turn, and are counter-clockwise.
+func Triangulate(outer [][]float64, holes ...[][]float64) []int {
+	pts := append([][]float64{}, outer...)
+	ring := make([]int, len(outer))
+	for i := range ring {
+		ring[i] = i
+	}
+
+	// Merge the holes in order of decreasing maximum x so most bridges go to the right
+	hs := make([]ringHole, 0, len(holes))
+	for _, h := range holes {
+		if len(h) < 3 {
+			continue
+		}
+		inds := make([]int, len(h))
+		mi := 0
+		for i, pt := range h {
+			inds[i] = len(pts)
+			pts = append(pts, pt)
+			if pt[0] > h[mi][0] {
+				mi = i
+			}
+		}
+		hs = append(hs, ringHole{inds, mi})
+	}
+	sort.SliceStable(hs, func(i, j int) bool {
+		return pts[hs[i].inds[hs[i].mi]][0] > pts[hs[j].inds[hs[j].mi]][0]
+	})
+	for i, h := range hs {
+		ring = bridgeHole(pts, ring, h, hs[i+1:])
+	}
+
+	return earClip(pts, ring)
+}
+
+// ringHole is a hole's point indices and the index within them of its rightmost point.
+type ringHole struct {
+	inds []int
+	mi   int
+}
+
+// bridgeHole splices the hole into the ring via the closest ring point, to the right of the hole's
+// rightmost point, that can be reached without crossing the ring or any of the holes still to be
+// merged. If there isn't one, then the closest such pair of hole and ring points is used instead. The
+// hole is only left out if none of its points can see the ring, which can't happen unless the rings
+// cross.
+func bridgeHole(pts [][]float64, ring []int, h ringHole, rest []ringHole) []int {
+	n, nh := len(ring), len(h.inds)
+	best, bk, bd := -1, -1, math.MaxFloat64
+	try := func(k int, right bool) {
+		m := pts[h.inds[k]]
+		hp, hn := pts[h.inds[(k+nh-1)%nh]], pts[h.inds[(k+1)%nh]]
+		for i, vi := range ring {
+			v := pts[vi]
+			if right && v[0] < m[0] {
+				continue
+			}
+			d := DistanceESquared(m, v)
+			if d >= bd {
+				continue
+			}
+			a, b := pts[ring[(i+n-1)%n]], pts[ring[(i+1)%n]]
+			if !locallyInside(a, v, b, m) || !locallyInside(hp, m, hn, v) ||
+				bridgeCrosses(pts, ring, m, v) || bridgeCrosses(pts, h.inds, m, v) {
+				continue
+			}
+			blocked := false
+			for _, o := range rest {
+				if bridgeCrosses(pts, o.inds, m, v) {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				best, bk, bd = i, k, d
+			}
+		}
+	}
+	try(h.mi, true)
+	for k := 0; best < 0 && k < nh; k++ {
+		try(k, false)
+	}
+	if best < 0 {
+		return ring
+	}
+
+	// ring[:best+1], hole from bk around to bk, ring[best:]
+	res := make([]int, 0, n+nh+2)
+	res = append(res, ring[:best+1]...)
+	for k := range nh + 1 {
+		res = append(res, h.inds[(bk+k)%nh])
+	}
+	return append(res, ring[best:]...)
+}
+
+// locallyInside returns true if p lies within the interior angle at v of a counter-clockwise polygon
+// where a precedes v and b follows it.
+func locallyInside(a, v, b, p []float64) bool {
+	if TriArea(a, v, b) >= 0 {
+		return TriArea(a, v, p) > 0 && TriArea(v, b, p) > 0
+	}
+	return TriArea(a, v, p) > 0 || TriArea(v, b, p) > 0
+}
+
+// bridgeCrosses returns true if the segment m-v crosses any of the ring's edges that don't end at
+// either m or v.
+func bridgeCrosses(pts [][]float64, ring []int, m, v []float64) bool {
+	n := len(ring)
+	for i := range n {
+		a, b := pts[ring[i]], pts[ring[(i+1)%n]]
+		if EqualsP(a, v) || EqualsP(b, v) || EqualsP(a, m) || EqualsP(b, m) {
+			continue
+		}
+		if SegmentsIntersect(m, v, a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// earClip triangulates the simple counter-clockwise ring of point indices by repeatedly removing
+// ears, i.e. convex vertices whose triangle with their neighbors contains no other vertex.
+func earClip(pts [][]float64, ring []int) []int {
+	n := len(ring)
+	res := make([]int, 0, 3*max(n-2, 0))
+	prev, next := make([]int, n), make([]int, n)
+	for i := range n {
+		prev[i], next[i] = (i+n-1)%n, (i+1)%n
+	}
+	remove := func(i int) {
+		next[prev[i]], prev[next[i]] = next[i], prev[i]
+		n--
+	}
+
+	i, stall := 0, 0
+	for n > 2 {
+		p, q := prev[i], next[i]
+		a, v, b := pts[ring[p]], pts[ring[i]], pts[ring[q]]
+		area := TriArea(a, v, b)
+		switch {
+		case math.Abs(area) < Epsilon*Epsilon:
+			// Collinear or spike, drop the vertex
+			remove(i)
+			i, stall = q, 0
+		case area > 0 && (stall > n || isEar(pts, ring, prev, next, p, i, q)):
+			// An ear, or forced when no ear can be found
+			res = append(res, ring[p], ring[i], ring[q])
+			remove(i)
+			i, stall = q, 0
+		default:
+			i = q
+			stall++
+			if stall > 2*n {
+				// Nothing convex remains
+				return res
+			}
+		}
+	}
+	return res
+}
+
+// isEar returns true if no other reflex vertex of the ring lies within the triangle p, i, q. Convex
+// vertices can't lie within an ear without a reflex one doing so too, and those on the triangle's
+// edges, as happens when a bridge or hole edge is collinear with the ring, would otherwise block it.
+// Vertices coincident with the triangle's, such as those at either end of a bridge, are ignored.
+func isEar(pts [][]float64, ring, prev, next []int, p, i, q int) bool {
+	a, v, b := pts[ring[p]], pts[ring[i]], pts[ring[q]]
+	for j := next[q]; j != p; j = next[j] {
+		pt := pts[ring[j]]
+		if EqualsP(pt, a) || EqualsP(pt, v) || EqualsP(pt, b) {
+			continue
+		}
+		if TriArea(pts[ring[prev[j]]], pt, pts[ring[next[j]]]) > 0 {
+			continue
+		}
+		if TriArea(a, v, pt) >= 0 && TriArea(v, b, pt) >= 0 && TriArea(b, a, pt) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package graphics2d
+
+import "github.com/jphsd/graphics2d/util"
+
+// DelaunayTriangles returns the Delaunay triangulation of the points as closed, counter-clockwise
+// triangular paths.
+func DelaunayTriangles(pts ...[]float64) []*Path {
+	tris := util.Delaunay(pts...)
+	res := make([]*Path, 0, len(tris)/3)
+	for i := 0; i < len(tris); i += 3 {
+		res = append(res, Polygon(pts[tris[i]], pts[tris[i+1]], pts[tris[i+2]]))
+	}
+	return res
+}
+
+// VoronoiCells returns the Voronoi cells of the seeds clipped to the shape, in the same order as the
+// seeds. A cell may have several paths if the shape is concave or has holes, and has none if it lies
+// outside of the shape or its seed is a repeat of an earlier seed. Returns nil if the shape has no
+// paths or there are no seeds.
+func (s *Shape) VoronoiCells(seeds ...[]float64) []*Shape {
+	if len(s.paths) == 0 || len(seeds) == 0 {
+		return nil
+	}
+	// Resolve the shape once, rather than for every cell
+	rs := resolveShapes([]*Shape{s}, func(w []int) bool { return s.rule.Inside(w[0]) })
+	bb := s.BoundingBox()
+	res := make([]*Shape, len(seeds))
+	for i, cell := range util.VoronoiCells(bb, seeds...) {
+		if cell == nil {
+			res[i] = &Shape{}
+			continue
+		}
+		res[i] = rs.clipConvex(cell)
+	}
+	return res
+}
+
+// clipConvex returns the region of the resolved shape that lies within the convex polygon. An outline
+// that doesn't cross the polygon and isn't inside it adds the same winding number everywhere within
+// it, so only the outlines that do are resolved against the polygon. If there are none, then the
+// polygon is either wholly inside or wholly outside of the shape.
+func (s *Shape) clipConvex(poly [][]float64) *Shape {
+	cell := Polygon(poly...)
+	if cell.Area() < 0 {
+		cell = cell.Reverse()
+	}
+	cbb := cell.BoundingBox()
+	near, far := &Shape{}, &Shape{}
+	for _, path := range s.paths {
+		if util.BBOverlap(path.BoundingBox(), cbb) &&
+			(len(path.Intersections(cell)) > 0 || cell.PointInPathRule(path.steps[0][0], NonZero)) {
+			near.AddPaths(path)
+		} else {
+			far.AddPaths(path)
+		}
+	}
+
+	// The centroid of the vertices is inside the convex polygon
+	ctr := []float64{0, 0}
+	for _, pt := range poly {
+		ctr[0] += pt[0]
+		ctr[1] += pt[1]
+	}
+	n := float64(len(poly))
+	ctr[0], ctr[1] = ctr[0]/n, ctr[1]/n
+	w0 := far.windingNumber(ctr)
+
+	if len(near.paths) == 0 {
+		if w0 > 0 {
+			return NewShape(cell)
+		}
+		return &Shape{}
+	}
+	return resolveShapes([]*Shape{NewShape(cell), near}, func(w []int) bool {
+		return w[0] != 0 && w0+w[1] > 0
+	})
+}
+
+// LloydRelax performs n iterations of Lloyd's algorithm on the seeds, moving each to the centroid of
+// its Voronoi cell within the shape, and returns the new seeds. The seeds become more evenly spaced
+// with each iteration. Seeds with empty cells are left where they are.
+func (s *Shape) LloydRelax(n int, seeds ...[]float64) [][]float64 {
+	res := make([][]float64, len(seeds))
+	for i, seed := range seeds {
+		res[i] = []float64{seed[0], seed[1]}
+	}
+	for range n {
+		for i, cell := range s.VoronoiCells(res...) {
+			// The cells are already resolved, so their paths' moments can be summed directly
+			var rm rawMoments
+			for _, path := range cell.paths {
+				rm.add(path.rawMoments())
+			}
+			if rm.a > 0 {
+				res[i] = rm.moments().Centroid
+			}
+		}
+	}
+	return res
+}
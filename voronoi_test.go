@@ -0,0 +1,94 @@
+package graphics2d_test
+
+import (
+	"fmt"
+	g2d "github.com/jphsd/graphics2d"
+	"github.com/jphsd/graphics2d/util"
+	"math/rand"
+)
+
+// Demonstrates Delaunay triangulation, Voronoi cells clipped to a shape and Lloyd relaxation.
+func ExampleShape_VoronoiCells() {
+	pts := [][]float64{{0, 0}, {100, 0}, {100, 100}, {0, 100}, {50, 50}}
+	fmt.Printf("%d triangles\n", len(g2d.DelaunayTriangles(pts...)))
+
+	square := g2d.NewShape(g2d.Polygon(pts[:4]...))
+	seeds := [][]float64{{40, 40}, {60, 40}, {60, 60}, {40, 60}}
+	for _, cell := range square.VoronoiCells(seeds...) {
+		fmt.Printf("cell area %.2f\n", cell.Area())
+	}
+
+	seeds = [][]float64{{10, 10}, {20, 10}, {15, 20}, {12, 15}}
+	for _, seed := range square.LloydRelax(50, seeds...) {
+		fmt.Printf("seed %.1f,%.1f\n", seed[0], seed[1])
+	}
+	// Output:
+	// 4 triangles
+	// cell area 2500.00
+	// cell area 2500.00
+	// cell area 2500.00
+	// cell area 2500.00
+	// seed 25.0,25.0
+	// seed 75.0,25.0
+	// seed 75.0,75.0
+	// seed 25.0,75.0
+}
+
+// Demonstrates that the Delaunay triangulation of random points covers their convex hull, so has
+// 2n-2-h triangles for n points with h on the hull, and that no point lies within the circumcircle of
+// any of the triangles.
+func ExampleDelaunayTriangles() {
+	rng := rand.New(rand.NewSource(1))
+	pts := make([][]float64, 2000)
+	for i := range pts {
+		pts[i] = []float64{rng.Float64() * 1000, rng.Float64() * 1000}
+	}
+
+	tris := g2d.DelaunayTriangles(pts...)
+	h := len(util.ConvexHull(pts...))
+	fmt.Printf("%d triangles, expected %d\n", len(tris), 2*len(pts)-2-h)
+
+	inside := 0
+	for _, tri := range tris {
+		steps := tri.Steps()
+		cc := util.Circumcircle(steps[0][0], steps[1][0], steps[2][0])
+		for _, pt := range pts {
+			if util.DistanceE(pt, cc[:2]) < cc[2]-1e-6 {
+				inside++
+			}
+		}
+	}
+	fmt.Printf("%d points within circumcircles\n", inside)
+	// Output:
+	// 3979 triangles, expected 3979
+	// 0 points within circumcircles
+}
+
+// Demonstrates Voronoi cells clipped to a curved shape with a hole. The hole lies wholly within the
+// first cell and the cells together cover the shape.
+func ExampleShape_LloydRelax() {
+	shape := g2d.NewShape(g2d.Circle([]float64{0, 0}, 100), g2d.Circle([]float64{40, 30}, 5))
+	shape.SetFillRule(g2d.EvenOdd)
+
+	seeds := [][]float64{{50, 50}, {-50, 50}, {-50, -50}, {50, -50}}
+	for _, cell := range shape.VoronoiCells(seeds...) {
+		fmt.Printf("cell %d paths, area %.2f\n", len(cell.Paths()), cell.Area())
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	seeds = make([][]float64, 50)
+	for i := range seeds {
+		seeds[i] = []float64{rng.Float64()*140 - 70, rng.Float64()*140 - 70}
+	}
+	total := 0.0
+	for _, cell := range shape.VoronoiCells(shape.LloydRelax(3, seeds...)...) {
+		total += cell.Area()
+	}
+	fmt.Printf("cells cover %.2f of %.2f\n", total, shape.Area())
+	// Output:
+	// cell 2 paths, area 7775.48
+	// cell 1 paths, area 7854.02
+	// cell 1 paths, area 7854.02
+	// cell 1 paths, area 7854.02
+	// cells cover 31337.52 of 31337.52
+}